@@ -0,0 +1,293 @@
+// Package aoctest is a fake Advent of Code server for end-to-end tests.
+//
+// It serves inputs, puzzle pages, answer submissions and private leaderboards the way adventofcode.com does,
+// including its error pages, so aoc can be pointed at it with -base-url:
+//
+//	server := aoctest.NewServer(aoctest.NewClock(time.Date(2022, time.December, 5, 0, 0, 0, 0, aoctest.EventLocation)))
+//	defer server.Close()
+//	server.SetInput(2022, 1, "1000\n2000\n")
+package aoctest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultSession is the session the server accepts unless Session is changed
+const DefaultSession = "aoctest"
+
+// EventLocation is UTC-5, where puzzles are unlocked at midnight
+var EventLocation = time.FixedZone("EST", -5*60*60)
+
+// Verdict is how the server responds to an answer
+type Verdict string
+
+const (
+	Correct    Verdict = "correct"
+	Wrong      Verdict = "wrong"
+	TooRecent  Verdict = "too_recent"
+	WrongLevel Verdict = "wrong_level"
+)
+
+// messages of the server, as adventofcode.com words them
+const (
+	lockedMessage     = "Please don't repeatedly request this endpoint before it unlocks! The calendar countdown is synchronized with the server time; the link will be enabled on the calendar the instant this puzzle becomes available."
+	loggedOutMessage  = "Puzzle inputs differ by user.  Please log in to get your puzzle input."
+	notFoundMessage   = "404 Not Found"
+	correctMessage    = "That's the right answer! You are one gold star closer to saving your vacation."
+	wrongMessage      = "That's not the right answer. If you're stuck, make sure you're using the full input data. Please wait %s before trying again."
+	tooRecentMessage  = "You gave an answer too recently; you have to wait after submitting an answer before trying again. You have %s left to wait."
+	wrongLevelMessage = "You don't seem to be solving the right level. Did you already complete it?"
+)
+
+var (
+	dayPattern         = regexp.MustCompile(`^/(\d{4})/day/(\d{1,2})(/input|/answer)?$`)
+	leaderboardPattern = regexp.MustCompile(`^/(\d{4})/leaderboard/private(?:/view/(\d+)\.json)?$`)
+)
+
+type dayKey struct {
+	year, day int
+}
+
+type partKey struct {
+	year, day, part int
+}
+
+// Server is a fake Advent of Code, its URL is what aoc's base url should be set to
+type Server struct {
+	*httptest.Server
+	Clock *Clock
+	// Session is the only session the server accepts, any other is logged out. Set it before the first request.
+	Session string
+	// Lockout is how long a wrong answer stops any more answers for the day. Set it before the first request.
+	Lockout time.Duration
+
+	mu           sync.Mutex
+	inputs       map[dayKey]string
+	puzzles      map[dayKey]string
+	answers      map[partKey]string
+	verdicts     map[partKey]Verdict
+	solved       map[partKey]bool
+	lockedUntil  map[dayKey]time.Time
+	leaderboards map[string]string
+	requests     []string
+}
+
+// NewServer starts a fake Advent of Code at the time of clock, it has to be closed when the test is done
+func NewServer(clock *Clock) *Server {
+	s := &Server{
+		Clock:        clock,
+		Session:      DefaultSession,
+		Lockout:      time.Minute,
+		inputs:       map[dayKey]string{},
+		puzzles:      map[dayKey]string{},
+		answers:      map[partKey]string{},
+		verdicts:     map[partKey]Verdict{},
+		solved:       map[partKey]bool{},
+		lockedUntil:  map[dayKey]time.Time{},
+		leaderboards: map[string]string{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// SetInput sets the input of a day
+func (s *Server) SetInput(year, day int, input string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inputs[dayKey{year, day}] = input
+}
+
+// SetPuzzle sets the HTML of a day's puzzle page, days without one get a page with only a title
+func (s *Server) SetPuzzle(year, day int, page string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.puzzles[dayKey{year, day}] = page
+}
+
+// SetAnswer sets the right answer of a part
+func (s *Server) SetAnswer(year, day, part int, answer string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.answers[partKey{year, day, part}] = answer
+}
+
+// SetVerdict makes every answer to a part get verdict, whatever the answer is. An empty verdict checks answers again.
+func (s *Server) SetVerdict(year, day, part int, verdict Verdict) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if verdict == "" {
+		delete(s.verdicts, partKey{year, day, part})
+		return
+	}
+	s.verdicts[partKey{year, day, part}] = verdict
+}
+
+// SetLeaderboard sets the JSON of a private leaderboard
+func (s *Server) SetLeaderboard(year int, id, leaderboard string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leaderboards[fmt.Sprintf("%d/%s", year, id)] = leaderboard
+}
+
+// Solved returns whether a right answer was submitted for a part
+func (s *Server) Solved(year, day, part int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.solved[partKey{year, day, part}]
+}
+
+// Requests returns every request the server got, ie. GET /2022/day/1/input
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	if match := dayPattern.FindStringSubmatch(r.URL.Path); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		day, _ := strconv.Atoi(match[2])
+		switch match[3] {
+		case "":
+			s.servePuzzle(w, r, dayKey{year, day})
+		case "/input":
+			s.serveInput(w, r, dayKey{year, day})
+		case "/answer":
+			s.serveAnswer(w, r, dayKey{year, day})
+		}
+		return
+	}
+
+	if match := leaderboardPattern.FindStringSubmatch(r.URL.Path); match != nil && r.Method == http.MethodGet {
+		year, _ := strconv.Atoi(match[1])
+		s.serveLeaderboard(w, r, year, match[2])
+		return
+	}
+
+	http.Error(w, notFoundMessage, http.StatusNotFound)
+}
+
+func (s *Server) loggedIn(r *http.Request) bool {
+	cookie, err := r.Cookie("session")
+	return err == nil && cookie.Value == s.Session
+}
+
+func (s *Server) unlocked(key dayKey) bool {
+	unlock := time.Date(key.year, time.December, key.day, 0, 0, 0, 0, EventLocation)
+	return key.day >= 1 && key.day <= 25 && !s.Clock.Now().Before(unlock)
+}
+
+func (s *Server) servePuzzle(w http.ResponseWriter, r *http.Request, key dayKey) {
+	if r.Method != http.MethodGet || !s.unlocked(key) {
+		http.Error(w, notFoundMessage, http.StatusNotFound)
+		return
+	}
+
+	page, ok := s.puzzles[key]
+	if !ok {
+		page = fmt.Sprintf("<main>\n<article class=\"day-desc\"><h2>--- Day %d: Day %d ---</h2></article>\n</main>", key.day, key.day)
+	}
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprint(w, page)
+}
+
+func (s *Server) serveInput(w http.ResponseWriter, r *http.Request, key dayKey) {
+	input, ok := s.inputs[key]
+	switch {
+	case r.Method != http.MethodGet:
+		http.Error(w, notFoundMessage, http.StatusNotFound)
+	case !s.unlocked(key):
+		http.Error(w, lockedMessage, http.StatusNotFound)
+	case !s.loggedIn(r):
+		http.Error(w, loggedOutMessage, http.StatusBadRequest)
+	case !ok:
+		http.Error(w, notFoundMessage, http.StatusNotFound)
+	default:
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, input)
+	}
+}
+
+func (s *Server) serveAnswer(w http.ResponseWriter, r *http.Request, key dayKey) {
+	if r.Method != http.MethodPost || !s.unlocked(key) {
+		http.Error(w, notFoundMessage, http.StatusNotFound)
+		return
+	}
+	if !s.loggedIn(r) {
+		http.Error(w, loggedOutMessage, http.StatusBadRequest)
+		return
+	}
+
+	part, err := strconv.Atoi(r.PostFormValue("level"))
+	if err != nil {
+		http.Error(w, notFoundMessage, http.StatusBadRequest)
+		return
+	}
+	verdict, message := s.judge(partKey{key.year, key.day, part}, r.PostFormValue("answer"))
+	if verdict == Wrong {
+		s.lockedUntil[key] = s.Clock.Now().Add(s.Lockout)
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprintf(w, "<main>\n<article><p>%s</p></article>\n</main>", message)
+}
+
+// judge returns the verdict of an answer to a part, and marks the part solved if it is right
+func (s *Server) judge(key partKey, answer string) (verdict Verdict, message string) {
+	day := dayKey{key.year, key.day}
+	verdict, forced := s.verdicts[key]
+	if !forced {
+		switch wait := s.lockedUntil[day].Sub(s.Clock.Now()); {
+		case wait > 0:
+			verdict = TooRecent
+		case key.part < 1 || key.part > 2 || s.solved[key] || (key.part == 2 && !s.solved[partKey{key.year, key.day, 1}]):
+			verdict = WrongLevel
+		case answer == s.answers[key]:
+			verdict = Correct
+		default:
+			verdict = Wrong
+		}
+	}
+
+	switch verdict {
+	case Correct:
+		s.solved[key] = true
+		return verdict, correctMessage
+	case Wrong:
+		return verdict, fmt.Sprintf(wrongMessage, s.Lockout)
+	case TooRecent:
+		wait := s.lockedUntil[day].Sub(s.Clock.Now())
+		if wait <= 0 {
+			wait = s.Lockout
+		}
+		return verdict, fmt.Sprintf(tooRecentMessage, wait.Round(time.Second))
+	}
+	return WrongLevel, wrongLevelMessage
+}
+
+func (s *Server) serveLeaderboard(w http.ResponseWriter, r *http.Request, year int, id string) {
+	leaderboard, ok := s.leaderboards[fmt.Sprintf("%d/%s", year, id)]
+	if id == "" || !ok || !s.loggedIn(r) {
+		if id != "" {
+			// like adventofcode.com, leaderboards that cannot be viewed redirect to the private leaderboard page
+			http.Redirect(w, r, fmt.Sprintf("/%d/leaderboard/private", year), http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<main>\n<article><p>You can join a private leaderboard by entering its join code here.</p></article>\n</main>")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, leaderboard)
+}
@@ -0,0 +1,169 @@
+package aoctest
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockServer(t *testing.T) *Server {
+	server := NewServer(NewClock(time.Date(2022, time.December, 2, 0, 0, 0, 0, EventLocation)))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func request(t *testing.T, server *Server, method, path, session string, form url.Values) (status int, body string) {
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if session != "" {
+		req.AddCookie(&http.Cookie{Name: "session", Value: session})
+	}
+
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	content, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, string(content)
+}
+
+func answer(t *testing.T, server *Server, part, value string) string {
+	_, body := request(t, server, "POST", "/2022/day/1/answer", DefaultSession, url.Values{"level": {part}, "answer": {value}})
+	return body
+}
+
+func TestServingInputs(t *testing.T) {
+	server := mockServer(t)
+	server.SetInput(2022, 1, "1\n2\n")
+	server.SetInput(2022, 3, "3\n")
+
+	t.Run("Should serve the input with the session", func(t *testing.T) {
+		status, body := request(t, server, "GET", "/2022/day/1/input", DefaultSession, nil)
+		if status != 200 || body != "1\n2\n" {
+			t.Errorf("Expected the input, got %d %q", status, body)
+		}
+	})
+
+	t.Run("Should refuse other sessions", func(t *testing.T) {
+		status, body := request(t, server, "GET", "/2022/day/1/input", "other", nil)
+		if status != 400 || !strings.Contains(body, "Please log in") {
+			t.Errorf("Expected to be logged out, got %d %q", status, body)
+		}
+	})
+
+	t.Run("Should unlock days with the clock", func(t *testing.T) {
+		status, body := request(t, server, "GET", "/2022/day/3/input", DefaultSession, nil)
+		if status != 404 || !strings.Contains(body, "before it unlocks") {
+			t.Errorf("Expected day 3 to be locked, got %d %q", status, body)
+		}
+
+		server.Clock.Advance(24 * time.Hour)
+		if status, body := request(t, server, "GET", "/2022/day/3/input", DefaultSession, nil); status != 200 || body != "3\n" {
+			t.Errorf("Expected day 3 to be unlocked, got %d %q", status, body)
+		}
+	})
+
+	t.Run("Should record requests", func(t *testing.T) {
+		requests := server.Requests()
+		if len(requests) == 0 || requests[0] != "GET /2022/day/1/input" {
+			t.Errorf("Unexpected requests %v", requests)
+		}
+	})
+}
+
+func TestServingPuzzles(t *testing.T) {
+	server := mockServer(t)
+	server.SetPuzzle(2022, 1, "<main><article class=\"day-desc\"><h2>--- Day 1: Calorie Counting ---</h2></article></main>")
+
+	if _, body := request(t, server, "GET", "/2022/day/1", "", nil); !strings.Contains(body, "Calorie Counting") {
+		t.Errorf("Expected the puzzle page, got %q", body)
+	}
+	if _, body := request(t, server, "GET", "/2022/day/2", "", nil); !strings.Contains(body, "--- Day 2: Day 2 ---") {
+		t.Errorf("Expected a page with a title, got %q", body)
+	}
+	if status, _ := request(t, server, "GET", "/2022/day/5", "", nil); status != 404 {
+		t.Errorf("Expected day 5 to be locked, got %d", status)
+	}
+}
+
+func TestSubmittingAnswers(t *testing.T) {
+	t.Run("Should lock out after a wrong answer", func(t *testing.T) {
+		server := mockServer(t)
+		server.SetAnswer(2022, 1, 1, "42")
+
+		if body := answer(t, server, "1", "41"); !strings.Contains(body, "That's not the right answer") {
+			t.Errorf("Expected a wrong answer, got %q", body)
+		}
+		if body := answer(t, server, "1", "42"); !strings.Contains(body, "You gave an answer too recently") {
+			t.Errorf("Expected to be locked out, got %q", body)
+		}
+
+		server.Clock.Advance(server.Lockout)
+		if body := answer(t, server, "1", "42"); !strings.Contains(body, "That's the right answer") {
+			t.Errorf("Expected the right answer, got %q", body)
+		}
+		if !server.Solved(2022, 1, 1) {
+			t.Error("Expected part 1 to be solved")
+		}
+	})
+
+	t.Run("Should refuse the wrong level", func(t *testing.T) {
+		server := mockServer(t)
+		server.SetAnswer(2022, 1, 1, "42")
+		server.SetAnswer(2022, 1, 2, "43")
+
+		if body := answer(t, server, "2", "43"); !strings.Contains(body, "You don't seem to be solving the right level") {
+			t.Errorf("Expected part 2 to be the wrong level, got %q", body)
+		}
+		answer(t, server, "1", "42")
+		if body := answer(t, server, "1", "42"); !strings.Contains(body, "You don't seem to be solving the right level") {
+			t.Errorf("Expected solved part 1 to be the wrong level, got %q", body)
+		}
+	})
+
+	t.Run("Should use the configured verdict", func(t *testing.T) {
+		server := mockServer(t)
+		server.SetVerdict(2022, 1, 1, TooRecent)
+
+		if body := answer(t, server, "1", "1"); !strings.Contains(body, "You have 1m0s left to wait") {
+			t.Errorf("Expected to be locked out, got %q", body)
+		}
+
+		server.SetVerdict(2022, 1, 1, Correct)
+		if body := answer(t, server, "1", "1"); !strings.Contains(body, "That's the right answer") {
+			t.Errorf("Expected the right answer, got %q", body)
+		}
+	})
+}
+
+func TestServingLeaderboards(t *testing.T) {
+	server := mockServer(t)
+	server.SetLeaderboard(2022, "1", `{"owner_id": 1, "event": "2022", "members": {}}`)
+
+	if status, body := request(t, server, "GET", "/2022/leaderboard/private/view/1.json", DefaultSession, nil); status != 200 || !strings.Contains(body, `"owner_id": 1`) {
+		t.Errorf("Expected the leaderboard, got %d %q", status, body)
+	}
+
+	for _, test := range []struct{ path, session string }{
+		{"/2022/leaderboard/private/view/1.json", "other"},
+		{"/2022/leaderboard/private/view/2.json", DefaultSession},
+	} {
+		status, body := request(t, server, "GET", test.path, test.session, nil)
+		if status != 200 || strings.Contains(body, "owner_id") {
+			t.Errorf("Expected %s to redirect to the private leaderboard page, got %d %q", test.path, status, body)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// a day regresses when its mean time is this much slower than the baseline
+const regressionThreshold = 0.1
+
+// BenchResult is how a day's solution performed over several runs
+type BenchResult struct {
+	Day       int           `json:"day"`
+	Runs      int           `json:"runs"`
+	Mean      time.Duration `json:"mean_ns"`
+	Min       time.Duration `json:"min_ns"`
+	PeakRSS   int64         `json:"peak_rss_bytes"`
+	Baseline  time.Duration `json:"baseline_ns,omitempty"`
+	Regressed bool          `json:"regressed"`
+}
+
+// BenchReport is the results for every day of a year that has a solution and an input
+type BenchReport struct {
+	Year  int           `json:"year"`
+	Days  []BenchResult `json:"days"`
+	Total time.Duration `json:"total_ns"`
+}
+
+// Regressions returns the days that are slower than their baseline
+func (r BenchReport) Regressions() (days []int) {
+	for _, result := range r.Days {
+		if result.Regressed {
+			days = append(days, result.Day)
+		}
+	}
+	return days
+}
+
+// baselinePath returns path, or bench.json in the year's directory when path is empty
+func baselinePath(path string, year int) string {
+	if path != "" {
+		return path
+	}
+	return filepath.Join(strconv.Itoa(year), "bench.json")
+}
+
+// measureRun runs the solution once, returning how long it took and the most memory it used
+func measureRun(dir, template string, content []byte) (elapsed time.Duration, rss int64, err error) {
+	cmd, err := buildCommand(template, inputFileName, content)
+	if err != nil {
+		return elapsed, rss, err
+	}
+	cmd.Dir = dir
+	cmd.Stdout = io.Discard
+
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		return elapsed, rss, fmt.Errorf("%s: %w", template, err)
+	}
+	return time.Since(start), peakRSS(cmd.ProcessState), nil
+}
+
+// benchCommand returns the command that runs a built solution, its run command when the pack has no bench command
+func (p Pack) benchCommand() string {
+	if p.Bench != "" {
+		return p.Bench
+	}
+	return p.Run
+}
+
+// benchDay builds the day's solution, runs it once to warm up and then measures it runs times
+func benchDay(pack Pack, dir string, day, runs int) (result BenchResult, err error) {
+	result = BenchResult{Day: day, Runs: runs}
+
+	content, err := readFile(filepath.Join(dir, inputFileName))
+	if err != nil {
+		return result, err
+	}
+
+	if pack.Build != "" {
+		if _, _, err := measureRun(dir, pack.Build, content); err != nil {
+			return result, err
+		}
+	}
+	if _, _, err := measureRun(dir, pack.benchCommand(), content); err != nil {
+		return result, err
+	}
+
+	var total time.Duration
+	for i := 0; i < runs; i++ {
+		elapsed, rss, err := measureRun(dir, pack.benchCommand(), content)
+		if err != nil {
+			return result, err
+		}
+
+		total += elapsed
+		if i == 0 || elapsed < result.Min {
+			result.Min = elapsed
+		}
+		if rss > result.PeakRSS {
+			result.PeakRSS = rss
+		}
+	}
+	result.Mean = total / time.Duration(runs)
+
+	return result, nil
+}
+
+// benchYear benchmarks every day of the year that has an input
+func benchYear(pack Pack, year, runs int) (report BenchReport, err error) {
+	if pack.benchCommand() == "" {
+		return report, fmt.Errorf("Template pack %s has no run command", pack.Name)
+	}
+	if runs < 1 {
+		return report, fmt.Errorf("Cannot benchmark %d runs", runs)
+	}
+
+	report.Year = year
+	for day := 1; day <= lastDay; day++ {
+		dir := dayDir(year, day)
+		if exists, err := checkFileExist(filepath.Join(dir, inputFileName)); err != nil || !exists {
+			continue
+		}
+
+		result, err := benchDay(pack, dir, day, runs)
+		if err != nil {
+			return report, fmt.Errorf("Day %d: %w", day, err)
+		}
+		report.Days = append(report.Days, result)
+		report.Total += result.Mean
+	}
+
+	if len(report.Days) == 0 {
+		return report, fmt.Errorf("No days with an input in %d", year)
+	}
+	return report, nil
+}
+
+// loadBaseline reads a stored report, a missing baseline is an empty report
+func loadBaseline(path string) (baseline BenchReport, err error) {
+	content, err := readFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return baseline, nil
+	}
+	if err != nil {
+		return baseline, err
+	}
+
+	if err := json.Unmarshal(content, &baseline); err != nil {
+		return baseline, fmt.Errorf("%s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+func saveBaseline(path string, report BenchReport) error {
+	content, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// compareBaseline marks the days that are slower than the baseline
+func compareBaseline(report *BenchReport, baseline BenchReport) {
+	baselines := map[int]time.Duration{}
+	for _, result := range baseline.Days {
+		baselines[result.Day] = result.Mean
+	}
+
+	for i := range report.Days {
+		result := &report.Days[i]
+		result.Baseline = baselines[result.Day]
+		result.Regressed = result.Baseline > 0 &&
+			float64(result.Mean) > float64(result.Baseline)*(1+regressionThreshold)
+	}
+}
+
+func formatBytes(size int64) string {
+	if size == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+}
+
+func formatChange(result BenchResult) string {
+	if result.Baseline == 0 {
+		return "-"
+	}
+	change := (float64(result.Mean)/float64(result.Baseline) - 1) * 100
+	if result.Regressed {
+		return fmt.Sprintf("%+.1f%% regressed", change)
+	}
+	return fmt.Sprintf("%+.1f%%", change)
+}
+
+// renderBench writes the report as a table
+func renderBench(w io.Writer, report BenchReport) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "Day\tRuns\tMean\tMin\tPeak RSS\tBaseline\tChange\t")
+
+	days := append([]BenchResult{}, report.Days...)
+	sort.Slice(days, func(i, j int) bool { return days[i].Day < days[j].Day })
+	for _, result := range days {
+		baseline := "-"
+		if result.Baseline > 0 {
+			baseline = result.Baseline.Round(time.Microsecond).String()
+		}
+		fmt.Fprintf(table, "%d\t%d\t%s\t%s\t%s\t%s\t%s\t\n", result.Day, result.Runs,
+			result.Mean.Round(time.Microsecond), result.Min.Round(time.Microsecond), formatBytes(result.PeakRSS), baseline, formatChange(result))
+	}
+	fmt.Fprintf(table, "Total\t\t%s\t\t\t\t\t\n", report.Total.Round(time.Microsecond))
+
+	return table.Flush()
+}
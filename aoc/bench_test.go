@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chdir moves into a temporary directory for the test
+func chdir(t *testing.T) string {
+	dir := t.TempDir()
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(previous) })
+	return dir
+}
+
+func TestBenchmarkingYear(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is not available")
+	}
+	readFile = os.ReadFile
+	chdir(t)
+
+	for _, day := range []int{1, 3} {
+		os.MkdirAll(dayDir(2022, day), 0o755)
+		os.WriteFile(filepath.Join(dayDir(2022, day), inputFileName), []byte("1\n"), 0o644)
+	}
+
+	t.Run("Should benchmark days with an input", func(t *testing.T) {
+		report, err := benchYear(Pack{Name: "cat", Run: "cat {input}"}, 2022, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(report.Days) != 2 || report.Days[0].Day != 1 || report.Days[1].Day != 3 {
+			t.Fatalf("Expected results for days 1 and 3, got %+v", report.Days)
+		}
+		for _, result := range report.Days {
+			if result.Runs != 2 || result.Mean <= 0 || result.Min > result.Mean {
+				t.Errorf("Unexpected result %+v", result)
+			}
+		}
+		if report.Total != report.Days[0].Mean+report.Days[1].Mean {
+			t.Errorf("Expected total to be the sum of the means, got %s", report.Total)
+		}
+	})
+
+	t.Run("Should build once and time the bench command", func(t *testing.T) {
+		pack := Pack{Name: "built", Run: "false", Build: "touch built", Bench: "cat built {input}"}
+		report, err := benchYear(pack, 2022, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Days) != 2 {
+			t.Errorf("Expected results for days 1 and 3, got %+v", report.Days)
+		}
+		if _, err := os.Stat(filepath.Join(dayDir(2022, 1), "built")); err != nil {
+			t.Errorf("Expected the solution to be built, got error: %s", err.Error())
+		}
+	})
+
+	t.Run("Should return error for failing solutions", func(t *testing.T) {
+		if _, err := benchYear(Pack{Name: "false", Run: "false"}, 2022, 1); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Should return error without any inputs", func(t *testing.T) {
+		if _, err := benchYear(Pack{Name: "cat", Run: "cat {input}"}, 2021, 1); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestComparingBaseline(t *testing.T) {
+	report := BenchReport{Year: 2022, Days: []BenchResult{
+		{Day: 1, Mean: 100 * time.Millisecond},
+		{Day: 2, Mean: 200 * time.Millisecond},
+		{Day: 3, Mean: 50 * time.Millisecond},
+	}}
+	baseline := BenchReport{Year: 2022, Days: []BenchResult{
+		{Day: 1, Mean: 95 * time.Millisecond},
+		{Day: 2, Mean: 100 * time.Millisecond},
+	}}
+
+	compareBaseline(&report, baseline)
+
+	if regressions := report.Regressions(); len(regressions) != 1 || regressions[0] != 2 {
+		t.Errorf("Expected only day 2 to regress, got %v", regressions)
+	}
+	if report.Days[2].Baseline != 0 {
+		t.Errorf("Expected no baseline for day 3, got %s", report.Days[2].Baseline)
+	}
+
+	var output bytes.Buffer
+	if err := renderBench(&output, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "+100.0% regressed") {
+		t.Errorf("Expected day 2 to be shown as regressed, got:\n%s", output.String())
+	}
+}
+
+func TestSavingBaseline(t *testing.T) {
+	readFile = os.ReadFile
+	path := filepath.Join(t.TempDir(), "2022", "bench.json")
+	report := BenchReport{Year: 2022, Days: []BenchResult{{Day: 1, Runs: 5, Mean: time.Second}}, Total: time.Second}
+
+	if err := saveBaseline(path, report); err != nil {
+		t.Fatal(err)
+	}
+
+	baseline, err := loadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(baseline.Days) != 1 || baseline.Days[0].Mean != time.Second {
+		t.Errorf("Expected the saved report, got %+v", baseline)
+	}
+
+	if missing, err := loadBaseline(filepath.Join(t.TempDir(), "missing.json")); err != nil || len(missing.Days) != 0 {
+		t.Errorf("Expected an empty baseline when missing, got %+v, %v", missing, err)
+	}
+}
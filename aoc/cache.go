@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Advent of Code asks that private leaderboards are not fetched more than once every 15 minutes
+const leaderboardPollInterval = 15 * time.Minute
+
+var userCacheDir = os.UserCacheDir
+
+type cachedLeaderboard struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+type cachedCalendar struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Body      string    `json:"body"`
+}
+
+// cachePath returns a path inside the aoc cache directory
+func cachePath(elem ...string) (string, error) {
+	dir, err := userCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{dir, "aoc"}, elem...)...), nil
+}
+
+func leaderboardCachePath(year int, id string) (string, error) {
+	return cachePath("leaderboards", fmt.Sprintf("%d-%s.json", year, id))
+}
+
+func readCachedLeaderboard(path string) (cached cachedLeaderboard, err error) {
+	content, err := readFile(path)
+	if err != nil {
+		return cached, err
+	}
+
+	if err = json.Unmarshal(content, &cached); err != nil {
+		return cached, err
+	}
+	return cached, nil
+}
+
+// writeCache writes a cached response as JSON
+func writeCache(path string, cached interface{}) error {
+	content, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
+
+// cachedFetchLeaderboard returns the cached leaderboard JSON and its age while it is fresher than the polling limit,
+// and only fetches it from advent of code once the cache has expired
+func cachedFetchLeaderboard(year int, id string, cookie http.Cookie, now time.Time) (body []byte, age time.Duration, err error) {
+	path, err := leaderboardCachePath(year, id)
+	if err != nil {
+		return body, age, err
+	}
+
+	cached, err := readCachedLeaderboard(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return body, age, err
+	}
+	if err == nil {
+		age = now.Sub(cached.FetchedAt)
+		// a cache from the future, ie. fetched with an older aoc under -now, has expired
+		if (age >= 0 && age < leaderboardPollInterval) || isOffline() {
+			logger.Info("cache hit", "path", path, "age", age.Round(time.Second))
+			return cached.Body, age, nil
+		}
+		logger.Debug("cache expired", "path", path, "age", age.Round(time.Second))
+	}
+	if isOffline() {
+		return body, 0, withCode(codeOffline, fmt.Errorf("Leaderboard %s of %d was never cached", id, year))
+	}
+
+	body, err = fetchLeaderboard(year, id, cookie)
+	if err != nil {
+		return body, 0, err
+	}
+
+	if _, err := parseLeaderboard(body); err != nil {
+		return body, 0, err
+	}
+
+	if err := writeCache(path, cachedLeaderboard{FetchedAt: now, Body: body}); err != nil {
+		return body, 0, err
+	}
+
+	if err := saveSnapshot(year, id, body, now); err != nil {
+		return body, 0, err
+	}
+
+	return body, 0, nil
+}
+
+// calendars differ by account, so they are cached per account
+func calendarCachePath(year int, sessionID string) (string, error) {
+	return cachePath("calendars", fmt.Sprintf("%d-%s.json", year, accountID(sessionID)))
+}
+
+// cachedFetchCalendar returns an event's calendar page, and only fetches it from advent of code
+// once the cache is older than the polling limit
+func cachedFetchCalendar(year int, cookie http.Cookie, now time.Time) (page []byte, err error) {
+	path, err := calendarCachePath(year, cookie.Value)
+	if err != nil {
+		return page, err
+	}
+
+	var cached cachedCalendar
+	content, err := readFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return page, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(content, &cached); err != nil {
+			return page, err
+		}
+		age := now.Sub(cached.FetchedAt)
+		if (age >= 0 && age < leaderboardPollInterval) || isOffline() {
+			logger.Info("cache hit", "path", path, "age", age.Round(time.Second))
+			return []byte(cached.Body), nil
+		}
+		logger.Debug("cache expired", "path", path, "age", age.Round(time.Second))
+	}
+	if isOffline() {
+		return page, withCode(codeOffline, fmt.Errorf("The calendar of %d was never cached", year))
+	}
+
+	page, err = fetchPage(calendarURL(year), cookie)
+	if err != nil {
+		return page, err
+	}
+	return page, writeCache(path, cachedCalendar{FetchedAt: now, Body: string(page)})
+}
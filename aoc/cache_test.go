@@ -0,0 +1,110 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+func mockCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	userCacheDir = func() (string, error) {
+		return dir, nil
+	}
+	readFile = os.ReadFile
+}
+
+func TestCachingLeaderboard(t *testing.T) {
+	cookie := http.Cookie{
+		Name:  "session",
+		Value: "abc123",
+	}
+	now := getNow(t, INSIDE_ADVENT_DATE)
+
+	t.Run("Should fetch and cache when there is no cache", func(t *testing.T) {
+		mockCacheDir(t)
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+
+		body, age, err := cachedFetchLeaderboard(2022, "1", cookie, now)
+		if err != nil {
+			t.Fatalf("Should not have error, got error: %s", err.Error())
+		}
+		if age != 0 {
+			t.Errorf("Expected a fresh leaderboard, got age %s", age)
+		}
+		if _, err := parseLeaderboard(body); err != nil {
+			t.Error(err)
+		}
+
+		path, _ := leaderboardCachePath(2022, "1")
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected the leaderboard to be cached, got error: %s", err.Error())
+		}
+	})
+
+	t.Run("Should use the cache within the polling limit", func(t *testing.T) {
+		mockCacheDir(t)
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+		if _, _, err := cachedFetchLeaderboard(2022, "1", cookie, now); err != nil {
+			t.Fatal(err)
+		}
+
+		client = &mockClient{res: http.Response{StatusCode: 500, Status: "500 Internal Server Error", Body: mockBody("")}}
+		_, age, err := cachedFetchLeaderboard(2022, "1", cookie, now.Add(10*time.Minute))
+		if err != nil {
+			t.Fatalf("Should not have fetched again, got error: %s", err.Error())
+		}
+		if age != 10*time.Minute {
+			t.Errorf("Expected age of 10m, got %s", age)
+		}
+	})
+
+	t.Run("Should fetch again after the polling limit", func(t *testing.T) {
+		mockCacheDir(t)
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+		if _, _, err := cachedFetchLeaderboard(2022, "1", cookie, now); err != nil {
+			t.Fatal(err)
+		}
+
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+		_, age, err := cachedFetchLeaderboard(2022, "1", cookie, now.Add(leaderboardPollInterval))
+		if err != nil {
+			t.Fatalf("Should not have error, got error: %s", err.Error())
+		}
+		if age != 0 {
+			t.Errorf("Expected a fresh leaderboard, got age %s", age)
+		}
+	})
+
+	t.Run("Should fetch again when the cache is from the future", func(t *testing.T) {
+		mockCacheDir(t)
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+		if _, _, err := cachedFetchLeaderboard(2022, "1", cookie, now.Add(24*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+		_, age, err := cachedFetchLeaderboard(2022, "1", cookie, now)
+		if err != nil {
+			t.Fatalf("Should not have error, got error: %s", err.Error())
+		}
+		if age != 0 {
+			t.Errorf("Expected a fresh leaderboard, got age %s", age)
+		}
+	})
+
+	t.Run("Should not cache an invalid leaderboard", func(t *testing.T) {
+		mockCacheDir(t)
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody("<html></html>")}}
+
+		if _, _, err := cachedFetchLeaderboard(2022, "1", cookie, now); err == nil {
+			t.Error("Expected an error")
+		}
+
+		path, _ := leaderboardCachePath(2022, "1")
+		if _, err := os.Stat(path); err == nil {
+			t.Error("Should not have cached the leaderboard")
+		}
+	})
+}
@@ -3,33 +3,236 @@ package main
 import (
 	"errors"
 	"flag"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var (
 	readFile = os.ReadFile
 	getEnv   = os.Getenv
-	initArgs = flag.Args
 )
 
-var sessionFlag = flag.String("session", "./session", "session token from advent of code")
+// flag values, set by the flags of the command being run
+var (
+	sessionFlag         string
+	allowSuspiciousFlag bool
+	outputFlag          string
+	execFlag            string
+	yearFlag            int
+	scoreFlag           string
+	daysFlag            bool
+	intervalFlag        time.Duration
+	webhookFlag         string
+	webhookFormatFlag   string
+	hookFlag            string
+	formatFlag          string
+	dbFlag              string
+	dayFlag             int
+	templatesFlag       string
+	langFlag            string
+	partFlag            int
+	submitFlag          bool
+	answerPatternFlag   string
+	ledgerFlag          string
+	runsFlag            int
+	baselineFlag        string
+	saveBaselineFlag    bool
+	jsonFlag            bool
+	verboseFlag         bool
+	debugFlag           bool
+	logFormatFlag       string
+	offlineFlag         bool
+	baseURLFlag         string
+	recordFlag          string
+	nowFlag             string
+	lockFlag            string
+)
 
 const SESSION_TOKEN = "AOC_SESSION"
 
-func run() (url, sessionParam string, err error) {
-	flag.Parse()
-	args := initArgs()
-	if len(args) < 1 || args[0] != "fetch" {
-		return url, sessionParam, errors.New("Did you want to call \"fetch\"?")
+// globalFlags are the flags of every command
+func globalFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&jsonFlag, "json", false, "print a JSON result object on stdout, and any other output on stderr")
+	flags.BoolVar(&verboseFlag, "v", false, "log every request to stderr")
+	flags.BoolVar(&debugFlag, "vv", false, "log every request with its headers, and what aoc does, to stderr")
+	flags.StringVar(&logFormatFlag, "log-format", "text", "log format: text or json")
+	flags.BoolVar(&offlineFlag, "offline", false, "never touch the network, only use cached leaderboards, fetched inputs and saved puzzle pages (or set AOC_OFFLINE=1)")
+	flags.StringVar(&baseURLFlag, "base-url", "", "url of a mirror or test server to use instead of "+defaultBaseURL+" (or set AOC_BASE_URL)")
+	flags.StringVar(&recordFlag, "record", "", "directory to save every response of advent of code to as test fixtures, without the session or personal data")
+	flags.StringVar(&nowFlag, "now", "", "pretend aoc starts at this time, ie. 2022-12-05 or 2022-12-05T00:00:00-05:00, to simulate december")
+}
+
+func sessionFlags(flags *flag.FlagSet) {
+	flags.StringVar(&sessionFlag, "session", "./session", "session token from advent of code, or a file containing it")
+}
+
+func inputFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&allowSuspiciousFlag, "allow-suspicious", false, "save the input even if it looks like an error page")
+	lockFlags(flags)
+}
+
+func yearFlags(flags *flag.FlagSet) {
+	flags.IntVar(&yearFlag, "year", 0, "event year, defaults to the latest event")
+}
+
+func packFlags(flags *flag.FlagSet) {
+	flags.StringVar(&templatesFlag, "templates", "", "directory of template packs to scaffold new days from")
+	flags.StringVar(&langFlag, "lang", "go", "template pack of a day's solution: go, python, rust or one of your own")
+}
+
+func answerFlags(flags *flag.FlagSet) {
+	flags.StringVar(&answerPatternFlag, "answer-regex", defaultAnswerPattern, "regex that finds the answer in a solution's output, {part} is replaced with the part")
+	flags.StringVar(&ledgerFlag, "ledger", "", "file recording accepted and rejected answers, defaults to ledger.json in the user config directory")
+}
+
+func fetchFlags(flags *flag.FlagSet) {
+	sessionFlags(flags)
+	inputFlags(flags)
+	flags.StringVar(&outputFlag, "o", "inputs.txt", "file to save the input to, or - to write it to stdout")
+	flags.StringVar(&execFlag, "exec", "", "command to run after fetching, {input} is replaced with the input's path")
+}
+
+func leaderboardFlags(flags *flag.FlagSet) {
+	sessionFlags(flags)
+	yearFlags(flags)
+	flags.StringVar(&scoreFlag, "score", "local", "leaderboard scoring mode: local, stars, time, delta or fair")
+	flags.BoolVar(&daysFlag, "days", false, "show a per day breakdown of the leaderboard")
+	flags.DurationVar(&intervalFlag, "interval", leaderboardPollInterval, "how often to poll when watching a leaderboard, at least 15m")
+	flags.StringVar(&webhookFlag, "webhook", "", "url to post leaderboard changes to")
+	flags.StringVar(&webhookFormatFlag, "webhook-format", "json", "webhook payload format: json, slack or discord")
+	flags.StringVar(&hookFlag, "hook", "", "command to run with leaderboard changes as JSON on stdin")
+	flags.StringVar(&formatFlag, "format", "csv", "leaderboard export format: csv, jsonl or sqlite")
+	flags.StringVar(&dbFlag, "db", "leaderboard.db", "SQLite database to export leaderboards to")
+	flags.IntVar(&dayFlag, "day", 0, "day to replay the leaderboard history of")
+}
+
+func statsFlags(flags *flag.FlagSet) {
+	sessionFlags(flags)
+	yearFlags(flags)
+}
+
+func newFlags(flags *flag.FlagSet) {
+	sessionFlags(flags)
+	inputFlags(flags)
+	packFlags(flags)
+}
+
+func runFlags(flags *flag.FlagSet) {
+	sessionFlags(flags)
+	packFlags(flags)
+	answerFlags(flags)
+	flags.IntVar(&partFlag, "part", 1, "part of the puzzle to run the solution for")
+	flags.BoolVar(&submitFlag, "submit", false, "submit the solution's answer if it is not known yet")
+}
+
+func benchFlags(flags *flag.FlagSet) {
+	yearFlags(flags)
+	packFlags(flags)
+	flags.IntVar(&runsFlag, "runs", 5, "how many times to run each solution when benchmarking")
+	flags.StringVar(&baselineFlag, "baseline", "", "benchmark baseline to compare against, defaults to bench.json in the year's directory")
+	flags.BoolVar(&saveBaselineFlag, "save-baseline", false, "save the benchmark results as the new baseline")
+}
+
+func verifyFlags(flags *flag.FlagSet) {
+	yearFlags(flags)
+	packFlags(flags)
+	answerFlags(flags)
+}
+
+func lockFlags(flags *flag.FlagSet) {
+	flags.StringVar(&lockFlag, "lock", "aoc.lock", "manifest recording the checksum of every fetched input")
+}
+
+// parseFetchArgs returns the puzzle url to fetch the input of
+func parseFetchArgs(args []string) (url string, err error) {
+	if len(args) < 1 {
+		return url, usageError("Please enter a url")
 	}
+	return expandURL(args[0]), nil
+}
+
+var leaderboardActions = []string{"watch", "export", "history"}
 
-	if len(args) < 2 {
-		return url, sessionParam, errors.New("Please enter a url")
+// parseLeaderboardArgs returns the leaderboard id, and the action if one of leaderboardActions came before it
+func parseLeaderboardArgs(args []string) (id, action string, err error) {
+	if len(args) > 0 {
+		for _, name := range leaderboardActions {
+			if args[0] == name {
+				args, action = args[1:], name
+				break
+			}
+		}
+	}
+	if len(args) < 1 {
+		return id, action, usageError("Please enter a leaderboard id")
+	}
+	if err := validateLeaderboardID(args[0]); err != nil {
+		return id, action, err
 	}
+	return args[0], action, nil
+}
 
-	return args[1], *sessionFlag, nil
+// watchSinks returns where to send leaderboard changes from the -webhook and -hook flags,
+// hooks write their output to stdout
+func watchSinks(stdout io.Writer) (sinks []sink, err error) {
+	if webhookFlag != "" {
+		s := webhookSink{url: webhookFlag, format: webhookFormatFlag}
+		if _, err := s.payload(nil); err != nil {
+			return sinks, err
+		}
+		sinks = append(sinks, s)
+	}
+	if hookFlag != "" {
+		sinks = append(sinks, commandSink{command: hookFlag, stdout: stdout})
+	}
+	return sinks, nil
+}
+
+// eventYear returns the -year flag, or the latest event if it was not set
+func eventYear(now time.Time) (year int, err error) {
+	if yearFlag == 0 {
+		return latestEvent(now), nil
+	}
+	if err := validateYear(yearFlag, now); err != nil {
+		return year, err
+	}
+	return yearFlag, nil
+}
+
+// sessionCookie makes the session cookie from the -session flag
+func sessionCookie() (cookie http.Cookie, err error) {
+	sessionID, err := grabSessionID(sessionFlag)
+	if err != nil {
+		return cookie, err
+	}
+	return makeCookie(sessionID)
+}
+
+// parsePuzzleArgs returns the year and day of the puzzle in the first argument, ie. 2022/5 or its url
+func parsePuzzleArgs(args []string, now time.Time) (year, day int, err error) {
+	if len(args) < 1 {
+		return year, day, usageError("Please enter a puzzle, ie. 2022/5")
+	}
+	return parsePuzzleURL(expandURL(args[0]), now)
+}
+
+var shortURLPattern = regexp.MustCompile(`^(\d{4})/(?:day/)?(\d{1,2})$`)
+
+// expandURL turns the short form year/day (ie. 2022/1) into a full puzzle url
+func expandURL(input string) string {
+	matches := shortURLPattern.FindStringSubmatch(input)
+	if matches == nil {
+		return input
+	}
+
+	day, _ := strconv.Atoi(matches[2])
+	return fmt.Sprintf("%s/%s/day/%d", baseURL(), matches[1], day)
 }
 
 func isPath(input string) bool {
@@ -37,15 +240,18 @@ func isPath(input string) bool {
 }
 
 func grabSessionID(sessionParam string) (sessionID string, err error) {
-	err = errors.New("No session id found")
+	err = withCode(codeSession, errors.New("No session id found"))
 
 	if isPath(sessionParam) {
 		fileContent, err := readFile(sessionParam)
 		if err != nil {
-			return sessionID, err
+			return sessionID, withCode(codeSession, err)
 		}
 
-		sessionID = strings.Fields(string(fileContent))[0]
+		// an empty session file has no fields
+		if fields := strings.Fields(string(fileContent)); len(fields) > 0 {
+			sessionID = fields[0]
+		}
 	} else {
 		sessionID = sessionParam
 	}
@@ -4,84 +4,35 @@ import (
 	"testing"
 )
 
-func TestParsingArgs(t *testing.T) {
-	t.Run("Error if no args in inputs", func(t *testing.T) {
-		args := []string{"command"}
-		initArgs = mockFlagArgs(args)
-
-		if _, _, err := run(); err == nil {
-			t.Error("Should return error if no args")
-		}
-	})
-
-	t.Run("First argument is fetch", func(t *testing.T) {
-		args := []string{"fetch", "url"}
-		initArgs = mockFlagArgs(args)
-
-		if _, _, err := run(); err != nil {
-			t.Errorf("First argument as fetch should be valid. Got error: %s", err.Error())
-		}
-	})
-
-	t.Run("Error if first argument not fetch", func(t *testing.T) {
-		var err error
-		args := []string{"notfetch"}
-		initArgs = mockFlagArgs(args)
-		expectedErrMsg := "Did you want to call \"fetch\"?"
-
-		if _, _, err = run(); err == nil {
-			t.Error("Expected an error")
-		}
-
-		errMsg := err.Error()
-		if errMsg != expectedErrMsg {
-			t.Errorf("Expected error: %s, but got error: %s", expectedErrMsg, errMsg)
-		}
-	})
-
-	t.Run("Error if no url passed as second argument", func(t *testing.T) {
-		var err error
-		args := []string{"fetch"}
-		initArgs = mockFlagArgs(args)
+func TestParsingFetchArgs(t *testing.T) {
+	t.Run("Error if no url passed", func(t *testing.T) {
 		expectedErrMsg := "Please enter a url"
 
-		if _, _, err = run(); err == nil {
-			t.Error("Expected and error")
+		_, err := parseFetchArgs([]string{})
+		if err == nil {
+			t.Fatal("Expected an error")
 		}
-
-		errMsg := err.Error()
-		if errMsg != expectedErrMsg {
-			t.Errorf("Expected error: %s, but got error: %s", expectedErrMsg, errMsg)
+		if err.Error() != expectedErrMsg {
+			t.Errorf("Expected error: %s, but got error: %s", expectedErrMsg, err.Error())
 		}
 	})
 
 	t.Run("Should return url", func(t *testing.T) {
-		args := []string{"fetch", "url"}
-		initArgs = mockFlagArgs(args)
-
-		url, _, err := run()
+		url, err := parseFetchArgs([]string{"url"})
 		if err != nil {
 			t.Errorf("Should not have error, got error: %s", err.Error())
 		}
 
-		if url != args[1] {
-			t.Errorf("Should return %s, got %s", args[1], url)
+		if url != "url" {
+			t.Errorf("Should return url, got %s", url)
 		}
 	})
 
-	t.Run("Should return a session field from session flag", func(t *testing.T) {
-		args := []string{"fetch", "url"}
-		initArgs = mockFlagArgs(args)
+	t.Run("Should expand short urls", func(t *testing.T) {
+		expected := "https://adventofcode.com/2022/day/1"
 
-		defaultSessionFlagVal := "./session"
-
-		_, sessionID, err := run()
-		if err != nil {
-			t.Errorf("Should not have an error, got error: %s", err.Error())
-		}
-
-		if sessionID != defaultSessionFlagVal {
-			t.Errorf("Should have session: %s, but got session: %s", defaultSessionFlagVal, sessionID)
+		if url, _ := parseFetchArgs([]string{"2022/1"}); url != expected {
+			t.Errorf("Should return %s, got %s", expected, url)
 		}
 	})
 }
@@ -96,6 +47,15 @@ func TestGrabSessionId(t *testing.T) {
 		}
 	})
 
+	t.Run("Returns error if the session file is empty", func(t *testing.T) {
+		readFile = mockReadFile([]byte(" \n"), nil)
+
+		sessionID, err := grabSessionID("/path")
+		if errorCode(err) != codeSession {
+			t.Errorf("Expected a session error, got %s %v", sessionID, err)
+		}
+	})
+
 	t.Run("Returns sessionID from file when it exists", func(t *testing.T) {
 		mockFile := []byte{'a', 'b', 'c'}
 		expected := "abc"
@@ -184,10 +144,58 @@ func TestCheckingIfPath(t *testing.T) {
 	})
 }
 
-func mockFlagArgs(args []string) func() []string {
-	return func() []string {
-		return args
-	}
+func TestParsingLeaderboardArgs(t *testing.T) {
+	t.Run("Should return leaderboard id", func(t *testing.T) {
+		id, action, err := parseLeaderboardArgs([]string{"123"})
+		if err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+		if id != "123" || action != "" {
+			t.Errorf("Expected id 123 without an action, got %s, %s", id, action)
+		}
+	})
+
+	t.Run("Should return leaderboard action", func(t *testing.T) {
+		id, action, err := parseLeaderboardArgs([]string{"watch", "123"})
+		if err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+		if id != "123" || action != "watch" {
+			t.Errorf("Expected to watch id 123, got %s, %s", id, action)
+		}
+	})
+
+	t.Run("Should return error without id", func(t *testing.T) {
+		if _, _, err := parseLeaderboardArgs([]string{"watch"}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Should return usage error for ids that are not numbers", func(t *testing.T) {
+		for _, id := range []string{"../../x", "-1", "12a"} {
+			if _, _, err := parseLeaderboardArgs([]string{"history", id}); errorCode(err) != codeUsage {
+				t.Errorf("Expected a usage error for %s, got %v", id, err)
+			}
+		}
+	})
+}
+
+func TestExpandingURL(t *testing.T) {
+	t.Run("Should expand year and day", func(t *testing.T) {
+		expected := "https://adventofcode.com/2022/day/1"
+
+		if url := expandURL("2022/01"); url != expected {
+			t.Errorf("Expected %s, got %s", expected, url)
+		}
+	})
+
+	t.Run("Should leave full urls alone", func(t *testing.T) {
+		input := "https://adventofcode.com/2022/day/1"
+
+		if url := expandURL(input); url != input {
+			t.Errorf("Expected %s, got %s", input, url)
+		}
+	})
 }
 
 func mockReadFile(file []byte, err error) func(string) ([]byte, error) {
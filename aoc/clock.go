@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	// the event's time zone is loaded from the embedded database on systems without tzdata
+	_ "time/tzdata"
+)
+
+// Clock tells aoc what time it is, so tests and -now can decide when puzzles unlock
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// shiftedClock runs offset ahead of, or behind, the clock it wraps
+type shiftedClock struct {
+	next   Clock
+	offset time.Duration
+}
+
+func (c shiftedClock) Now() time.Time {
+	return c.next.Now().Add(c.offset)
+}
+
+var clock Clock = systemClock{}
+
+// wallClock is the real time, for what aoc keeps on disk: the age of cached leaderboards, snapshots and the lock file.
+// Unlike clock it is never shifted by -now, so simulating december does not date them.
+var wallClock Clock = systemClock{}
+
+// nowFormats are the formats -now accepts, dates without a time zone are in the event's time zone
+var nowFormats = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// parseNow reads the time of -now
+func parseNow(value string) (now time.Time, err error) {
+	est, err := eventLocation()
+	if err != nil {
+		return now, err
+	}
+
+	for _, format := range nowFormats {
+		if now, err = time.ParseInLocation(format, value, est); err == nil {
+			return now, nil
+		}
+	}
+	return now, usageError(fmt.Sprintf("%s is not a time, use ie. 2022-12-05 or 2022-12-05T00:00:00-05:00", value))
+}
+
+// setupClock makes aoc start at the time of -now, which then runs on from there
+func setupClock() error {
+	if nowFlag == "" {
+		return nil
+	}
+
+	start, err := parseNow(nowFlag)
+	if err != nil {
+		return err
+	}
+	clock = shiftedClock{next: clock, offset: start.Sub(clock.Now())}
+	logger.Debug("clock shifted", "now", start)
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/streakinthesky/adventofcode-fetcher/aoc/aoctest"
+)
+
+func mockClock(t *testing.T, now time.Time) *aoctest.Clock {
+	mocked := aoctest.NewClock(now)
+	clock = mocked
+	t.Cleanup(func() { clock = systemClock{} })
+	return mocked
+}
+
+func TestParsingNow(t *testing.T) {
+	est := mockEventLocation(t)
+	tests := map[string]time.Time{
+		"2022-12-05":                time.Date(2022, time.December, 5, 0, 0, 0, 0, est),
+		"2022-12-05T06:30":          time.Date(2022, time.December, 5, 6, 30, 0, 0, est),
+		"2022-12-05T06:30:15":       time.Date(2022, time.December, 5, 6, 30, 15, 0, est),
+		"2022-12-05T05:00:00Z":      time.Date(2022, time.December, 5, 0, 0, 0, 0, est),
+		"2022-12-05T00:00:00-05:00": time.Date(2022, time.December, 5, 0, 0, 0, 0, est),
+	}
+
+	for value, expected := range tests {
+		now, err := parseNow(value)
+		if err != nil {
+			t.Errorf("Should not have error for %s, got error: %s", value, err.Error())
+		} else if !now.Equal(expected) {
+			t.Errorf("Expected %s for %s, got %s", expected, value, now)
+		}
+	}
+
+	if _, err := parseNow("december"); errorCode(err) != codeUsage {
+		t.Errorf("Expected a usage error, got %v", err)
+	}
+}
+
+func TestEventNow(t *testing.T) {
+	t.Run("Should use the clock", func(t *testing.T) {
+		mockClock(t, time.Date(2022, time.December, 5, 5, 0, 0, 0, time.UTC))
+
+		now, err := eventNow()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if now.Day() != 5 || now.Hour() != 0 {
+			t.Errorf("Expected midnight of the 5th in the event's time zone, got %s", now)
+		}
+	})
+
+	t.Run("Should start at -now", func(t *testing.T) {
+		mocked := mockClock(t, time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC))
+		nowFlag = "2022-12-05"
+		t.Cleanup(func() { nowFlag = "" })
+
+		if err := setupClock(); err != nil {
+			t.Fatal(err)
+		}
+		mocked.Advance(time.Hour)
+
+		now, err := eventNow()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := time.Date(2022, time.December, 5, 1, 0, 0, 0, mockEventLocation(t)); !now.Equal(expected) {
+			t.Errorf("Expected %s, got %s", expected, now)
+		}
+	})
+}
+
+func TestFetchingWithClock(t *testing.T) {
+	cookie := http.Cookie{Name: "session", Value: "abc123"}
+	url := "https://adventofcode.com/2022/day/2"
+	mocked := mockClock(t, time.Date(2022, time.December, 1, 23, 59, 59, 0, mockEventLocation(t)))
+	client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody("1\n")}}
+
+	if _, err := fetch(url, cookie); errorCode(err) != codeLocked {
+		t.Errorf("Expected day 2 to be locked, got %v", err)
+	}
+
+	mocked.Advance(time.Second)
+	if _, err := fetch(url, cookie); err != nil {
+		t.Errorf("Expected day 2 to be unlocked, got error: %s", err.Error())
+	}
+}
+
+func TestSimulatingDecember(t *testing.T) {
+	mockClock(t, time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC))
+
+	var stdout, stderr bytes.Buffer
+	if code := execute([]string{"__complete", "fetch", "2026/", "-now", "2026-12-03"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if suggestions := strings.Fields(stdout.String()); strings.Join(suggestions, " ") != "2026/1 2026/2 2026/3" {
+		t.Errorf("Expected the first 3 days of 2026, got %v", suggestions)
+	}
+}
+
+func TestCachingWithShiftedClock(t *testing.T) {
+	mockCacheDir(t)
+	mockClock(t, time.Now())
+	client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+
+	var stdout, stderr bytes.Buffer
+	if code := execute([]string{"leaderboard", "1", "-year", "2022", "-session", "abc123", "-now", "2099-12-05"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	path, _ := leaderboardCachePath(2022, "1")
+	cached, err := readCachedLeaderboard(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if age := time.Since(cached.FetchedAt); age < 0 || age > time.Minute {
+		t.Errorf("Expected the cache to be dated by the real time, got %s", cached.FetchedAt)
+	}
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// command is a subcommand of aoc, ie. aoc fetch
+type command struct {
+	name string
+	// args describes the arguments after the flags, ie. <url>
+	args    string
+	summary string
+	// description is shown in the command's help after the summary
+	description string
+	flags       func(flags *flag.FlagSet)
+	run         func(args []string, stdout, stderr io.Writer, result *Result) error
+	// complete suggests the command's arguments that start with word, for the completion scripts
+	complete func(word string, now time.Time) []string
+	// hidden commands are left out of the help
+	hidden bool
+}
+
+// usageError is a mistake in how aoc was called, it is shown with the command's usage
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// exitError is a command aoc ran that failed, aoc exits with exitCommand whatever the command's exit code was
+type exitError struct {
+	command string
+	code    int
+}
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("%s exited with %d", e.command, e.code)
+}
+
+// commands returns every subcommand, in the order they are listed in the help
+func commands() []command {
+	return []command{
+		{
+			name:        "fetch",
+			args:        "<url>",
+			summary:     "Fetch a puzzle's input",
+			description: "The url is a puzzle's url or year/day, ie. 2022/5. Inputs that were already fetched and have not changed are not fetched again.",
+			flags:       fetchFlags,
+			run:         fetchCommand,
+			complete:    completePuzzle,
+		},
+		{
+			name:        "leaderboard",
+			args:        "[watch|export|history] <id>",
+			summary:     "Show, watch, export or replay a private leaderboard",
+			description: "Leaderboards are cached for 15 minutes and a snapshot is kept every time one is fetched.",
+			flags:       leaderboardFlags,
+			run:         leaderboardCommand,
+			complete:    completeLeaderboard,
+		},
+		{
+			name:        "stats",
+			summary:     "Show your stars, times and ranks",
+			description: "Shows the stars of every event, or the times and ranks of each day with -year.",
+			flags:       statsFlags,
+			run:         statsCommand,
+		},
+		{
+			name:        "new",
+			args:        "<year/day>",
+			summary:     "Scaffold a day's solution from a template pack",
+			description: "Fetches the day's input and example, and renders the template pack into {year}/day{NN}.",
+			flags:       newFlags,
+			run:         newCommand,
+			complete:    completePuzzle,
+		},
+		{
+			name:        "run",
+			args:        "<year/day>",
+			summary:     "Run a day's solution and check or submit its answer",
+			description: "Answers are checked against the ledger, and only submitted with -submit when they are not known yet.",
+			flags:       runFlags,
+			run:         solveCommand,
+			complete:    completePuzzle,
+		},
+		{
+			name:        "bench",
+			summary:     "Benchmark every solution of a year",
+			description: "Days more than 10% slower than the baseline are regressions.",
+			flags:       benchFlags,
+			run:         benchCommand,
+		},
+		{
+			name:    "verify",
+			summary: "Check every solution still gets its accepted answers",
+			flags:   verifyFlags,
+			run:     verifyCommand,
+		},
+		{
+			name:        "lock",
+			args:        "verify",
+			summary:     "Check fetched inputs against the lock file",
+			description: "Does not need the network.",
+			flags:       lockFlags,
+			run:         lockCommand,
+			complete:    completeLock,
+		},
+		{
+			name:        "completion",
+			args:        "bash|zsh|fish",
+			summary:     "Print a shell completion script",
+			description: "Load it in your shell, ie. source <(aoc completion bash).",
+			flags:       func(*flag.FlagSet) {},
+			run:         completionCommand,
+			complete:    completeShell,
+		},
+		{
+			name:     "help",
+			args:     "[command]",
+			summary:  "Show the help of aoc or a command",
+			flags:    func(*flag.FlagSet) {},
+			run:      helpCommand,
+			complete: completeHelp,
+		},
+		{
+			name:    "__complete",
+			args:    "<command> [word]",
+			summary: "Suggest a command's arguments for the completion scripts",
+			flags:   func(*flag.FlagSet) {},
+			run:     completeCommand,
+			hidden:  true,
+		},
+	}
+}
+
+func findCommand(name string) (cmd command, ok bool) {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return cmd, false
+}
+
+// flagSet makes the command's flags, setting every flag value to its default
+func (c command) flagSet(output io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet("aoc "+c.name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	c.flags(flags)
+	globalFlags(flags)
+	flags.Usage = func() { c.usage(output, flags) }
+	return flags
+}
+
+func (c command) usage(w io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: aoc %s [flags] %s\n\n%s.\n", c.name, c.args, c.summary)
+	if c.description != "" {
+		fmt.Fprintf(w, "%s\n", c.description)
+	}
+
+	var hasFlags bool
+	flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprint(w, "\nFlags:\n")
+		flags.SetOutput(w)
+		flags.PrintDefaults()
+	}
+}
+
+// usage writes the help of aoc itself
+func usage(w io.Writer) error {
+	fmt.Fprint(w, "Usage: aoc <command> [flags] [args]\n\nCommands:\n")
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands() {
+		if !cmd.hidden {
+			fmt.Fprintf(table, "  %s\t%s\n", cmd.name, cmd.summary)
+		}
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprint(w, "\nRun \"aoc help <command>\" for a command's flags.\n")
+	return err
+}
+
+// helpCommand shows the help of aoc, or of the command in args
+func helpCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	if len(args) == 0 {
+		return usage(stdout)
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		return usageError(fmt.Sprintf("%s is not a command", args[0]))
+	}
+	cmd.usage(stdout, cmd.flagSet(stdout))
+	return nil
+}
+
+// allFlags has the flags of every command, to find the command when flags come before it
+func allFlags() *flag.FlagSet {
+	all := flag.NewFlagSet("aoc", flag.ContinueOnError)
+	for _, cmd := range commands() {
+		cmd.flagSet(io.Discard).VisitAll(func(f *flag.Flag) {
+			if all.Lookup(f.Name) == nil {
+				all.Var(f.Value, f.Name, f.Usage)
+			}
+		})
+	}
+	return all
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+// commandIndex returns where the command name is in args, skipping any flags before it
+func commandIndex(args []string) int {
+	all := allFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			if i+1 < len(args) {
+				return i + 1
+			}
+			return -1
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			return i
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := all.Lookup(name); f != nil && !isBoolFlag(f) {
+			i++
+		}
+	}
+	return -1
+}
+
+// parseInterspersed parses flags wherever they are in args, returning the other arguments.
+// Everything after -- is an argument.
+func parseInterspersed(flags *flag.FlagSet, args []string) (positional []string, err error) {
+	for {
+		if err := flags.Parse(args); err != nil {
+			return positional, err
+		}
+
+		rest := flags.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if parsed := len(args) - len(rest); parsed > 0 && args[parsed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// helpArgs turns flags without a command that ask for help, ie. aoc -h, into the help command.
+// It returns nil when args do not ask for help.
+func helpArgs(args []string) (help []string) {
+	asked := false
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return nil
+		case "-h", "--h", "-help", "--help":
+			asked = true
+		default:
+			help = append(help, arg)
+		}
+	}
+	if !asked {
+		return nil
+	}
+	return append(help, "help")
+}
+
+// dispatch finds the command in args, parses its flags and runs it. It returns the command if one was found.
+func dispatch(args []string, stdout, stderr io.Writer, result *Result) (cmd *command, err error) {
+	index := commandIndex(args)
+	if help := helpArgs(args); index < 0 && help != nil {
+		args, index = help, len(help)-1
+	}
+	if index < 0 {
+		return nil, usageError("Please enter a command")
+	}
+
+	found, ok := findCommand(args[index])
+	if !ok {
+		return nil, usageError(fmt.Sprintf("%s is not a command", args[index]))
+	}
+	cmd = &found
+	result.Command = cmd.name
+
+	flags := cmd.flagSet(io.Discard)
+	cmdArgs := append(append([]string{}, args[:index]...), args[index+1:]...)
+	positional, err := parseInterspersed(flags, cmdArgs)
+	if errors.Is(err, flag.ErrHelp) {
+		cmd.usage(stderr, flags)
+		return cmd, nil
+	}
+	if err != nil {
+		return cmd, usageError(err.Error())
+	}
+
+	if err := setupLogger(stderr); err != nil {
+		return cmd, err
+	}
+	if err := checkBaseURL(); err != nil {
+		return cmd, err
+	}
+	if err := setupClock(); err != nil {
+		return cmd, err
+	}
+	logger.Debug("running command", "command", cmd.name, "args", positional)
+
+	return cmd, cmd.run(positional, stdout, stderr, result)
+}
+
+// execute runs the command in args, which are the arguments without the program name, and returns the exit code.
+// With -json, the command's text goes to stderr and only its result is written to stdout.
+func execute(args []string, stdout, stderr io.Writer) int {
+	jsonOutput := wantsJSON(args)
+	output := stdout
+	if jsonOutput {
+		output = stderr
+	}
+
+	var result Result
+	cmd, err := dispatch(args, output, stderr, &result)
+	if jsonOutput {
+		if err := writeResult(stdout, result, err); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+		return exitCode(err)
+	}
+
+	var usageErr usageError
+	switch {
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "%s\n\n", err)
+		if cmd == nil {
+			usage(stderr)
+		} else {
+			cmd.usage(stderr, cmd.flagSet(io.Discard))
+		}
+	case err != nil:
+		fmt.Fprintln(stderr, err)
+	}
+	return exitCode(err)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsingInterspersedFlags(t *testing.T) {
+	var name string
+	var verbose bool
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&name, "name", "", "")
+	flags.BoolVar(&verbose, "v", false, "")
+
+	positional, err := parseInterspersed(flags, []string{"a", "-name", "x", "b", "-v", "--", "-c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"a", "b", "-c"}; !reflect.DeepEqual(positional, expected) {
+		t.Errorf("Expected %v, got %v", expected, positional)
+	}
+	if name != "x" || !verbose {
+		t.Errorf("Expected flags to be set, got %s, %t", name, verbose)
+	}
+}
+
+func TestFindingCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"fetch", "2022/1"}, 0},
+		{[]string{"-year", "2022", "bench"}, 2},
+		{[]string{"-year=2022", "-json", "bench"}, 2},
+		{[]string{"-submit", "run", "2022/1"}, 1},
+		{[]string{"--", "fetch"}, 1},
+		{[]string{"-json"}, -1},
+		{[]string{}, -1},
+	}
+
+	for _, test := range tests {
+		if index := commandIndex(test.args); index != test.expected {
+			t.Errorf("Expected command at %d in %v, got %d", test.expected, test.args, index)
+		}
+	}
+}
+
+func TestExecutingCommands(t *testing.T) {
+	readFile = os.ReadFile
+	lock := filepath.Join(t.TempDir(), "aoc.lock")
+
+	t.Run("Should show usage without a command", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute(nil, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit code 2, got %d", code)
+		}
+		if !strings.Contains(stderr.String(), "Commands:") {
+			t.Errorf("Expected usage, got %q", stderr.String())
+		}
+	})
+
+	t.Run("Should return error for unknown command", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute([]string{"fetc"}, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit code 2, got %d", code)
+		}
+		if !strings.Contains(stderr.String(), "fetc is not a command") {
+			t.Errorf("Expected an error, got %q", stderr.String())
+		}
+	})
+
+	t.Run("Should show help of every command", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute([]string{"help"}, &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+		}
+		for _, cmd := range commands() {
+			if !cmd.hidden && !strings.Contains(stdout.String(), cmd.name) {
+				t.Errorf("Expected %s in %q", cmd.name, stdout.String())
+			}
+		}
+	})
+
+	t.Run("Should show help of a command", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute([]string{"help", "fetch"}, &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), "aoc fetch [flags] <url>") || !strings.Contains(stdout.String(), "-exec") {
+			t.Errorf("Unexpected help %q", stdout.String())
+		}
+		if strings.Contains(stdout.String(), "-webhook") {
+			t.Errorf("Expected only the flags of fetch, got %q", stdout.String())
+		}
+	})
+
+	t.Run("Should show help for -h", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute([]string{"lock", "-h"}, &stdout, &stderr); code != 0 {
+			t.Errorf("Expected exit code 0, got %d", code)
+		}
+		if !strings.Contains(stderr.String(), "aoc lock") {
+			t.Errorf("Expected usage, got %q", stderr.String())
+		}
+	})
+
+	t.Run("Should show help of every command for -h without a command", func(t *testing.T) {
+		for _, args := range [][]string{{"-h"}, {"-v", "-help"}} {
+			var stdout, stderr bytes.Buffer
+			if code := execute(args, &stdout, &stderr); code != 0 {
+				t.Errorf("Expected exit code 0 for %v, got %d: %s", args, code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), "Usage: aoc <command>") {
+				t.Errorf("Expected the help of aoc for %v, got %q", args, stdout.String())
+			}
+		}
+	})
+
+	t.Run("Should return error for flags of other commands", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute([]string{"lock", "-webhook", "url", "verify"}, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit code 2, got %d", code)
+		}
+	})
+
+	t.Run("Should show usage for missing arguments", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute([]string{"lock"}, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit code 2, got %d", code)
+		}
+		if !strings.Contains(stderr.String(), "Usage: aoc lock") {
+			t.Errorf("Expected usage, got %q", stderr.String())
+		}
+	})
+
+	t.Run("Should parse flags before and after the command", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"-lock", lock, "lock", "verify"},
+			{"lock", "verify", "-lock", lock},
+		} {
+			var stdout, stderr bytes.Buffer
+			if code := execute(args, &stdout, &stderr); code != exitFailure {
+				t.Errorf("Expected exit code %d, got %d", exitFailure, code)
+			}
+			if !strings.Contains(stderr.String(), "No inputs recorded in "+lock) {
+				t.Errorf("Expected error for %s, got %q", lock, stderr.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed completions
+var completionTemplates embed.FS
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionFlag is a flag as the completion scripts need it
+type completionFlag struct {
+	Name  string
+	Usage string
+	Bool  bool
+}
+
+// completionEntry is a command as the completion scripts need it
+type completionEntry struct {
+	Name    string
+	Summary string
+	Flags   []completionFlag
+}
+
+// completionData is what the completion scripts are rendered with
+type completionData struct {
+	Commands []completionEntry
+	// ValueFlags are the flags of any command that take a value, ie. -year
+	ValueFlags []string
+}
+
+func commandFlags(cmd command) (flags []completionFlag) {
+	cmd.flagSet(io.Discard).VisitAll(func(f *flag.Flag) {
+		flags = append(flags, completionFlag{Name: f.Name, Usage: f.Usage, Bool: isBoolFlag(f)})
+	})
+	return flags
+}
+
+func newCompletionData() (data completionData) {
+	valueFlags := map[string]bool{}
+	for _, cmd := range commands() {
+		if cmd.hidden {
+			continue
+		}
+
+		flags := commandFlags(cmd)
+		for _, f := range flags {
+			if !f.Bool {
+				valueFlags[f.Name] = true
+			}
+		}
+		data.Commands = append(data.Commands, completionEntry{Name: cmd.name, Summary: cmd.summary, Flags: flags})
+	}
+
+	for name := range valueFlags {
+		data.ValueFlags = append(data.ValueFlags, name)
+	}
+	sort.Strings(data.ValueFlags)
+	return data
+}
+
+// quote single quotes s for sh and zsh
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single quotes s for fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// writeCompletion writes the completion script for shell
+func writeCompletion(w io.Writer, shell string) error {
+	funcs := template.FuncMap{"quote": quote, "fishQuote": fishQuote}
+	tmpl, err := template.New(shell+templateExt).Funcs(funcs).ParseFS(completionTemplates, "completions/"+shell+templateExt)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, newCompletionData())
+}
+
+// completionCommand writes the completion script for the shell in args
+func completionCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	if len(args) < 1 {
+		return usageError("Please enter a shell: bash, zsh or fish")
+	}
+	for _, shell := range completionShells {
+		if args[0] == shell {
+			return writeCompletion(stdout, shell)
+		}
+	}
+	return usageError(fmt.Sprintf("%s is not a supported shell, use bash, zsh or fish", args[0]))
+}
+
+// completeCommand writes the suggestions for a command's argument, one per line, for the completion scripts
+func completeCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	if len(args) < 1 {
+		return usageError("Please enter a command to complete")
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok || cmd.complete == nil {
+		return nil
+	}
+
+	var word string
+	if len(args) > 1 {
+		word = args[1]
+	}
+
+	now, err := eventNow()
+	if err != nil {
+		return err
+	}
+
+	for _, suggestion := range cmd.complete(word, now) {
+		fmt.Fprintln(stdout, suggestion)
+	}
+	return nil
+}
+
+func matching(word string, candidates []string) (suggestions []string) {
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions
+}
+
+// completePuzzle suggests the events until one is typed, ie. 2022/, and then that event's unlocked days
+func completePuzzle(word string, now time.Time) []string {
+	var candidates []string
+
+	yearPart, _, found := strings.Cut(word, "/")
+	if !found {
+		for year := latestEvent(now); year >= firstYear; year-- {
+			candidates = append(candidates, fmt.Sprintf("%d/", year))
+		}
+		return matching(word, candidates)
+	}
+
+	year, err := strconv.Atoi(yearPart)
+	if err != nil || validateYear(year, now) != nil {
+		return nil
+	}
+	for day := 1; day <= lastDay; day++ {
+		if unlockTime(year, day, now.Location()).After(now) {
+			break
+		}
+		candidates = append(candidates, fmt.Sprintf("%d/%d", year, day))
+	}
+	return matching(word, candidates)
+}
+
+func completeLeaderboard(word string, now time.Time) []string {
+	return matching(word, leaderboardActions)
+}
+
+func completeLock(word string, now time.Time) []string {
+	return matching(word, []string{"verify"})
+}
+
+func completeShell(word string, now time.Time) []string {
+	return matching(word, completionShells)
+}
+
+func completeHelp(word string, now time.Time) []string {
+	var names []string
+	for _, cmd := range commands() {
+		if !cmd.hidden {
+			names = append(names, cmd.name)
+		}
+	}
+	return matching(word, names)
+}
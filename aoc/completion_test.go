@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompletingPuzzles(t *testing.T) {
+	now := time.Date(2022, time.December, 3, 12, 0, 0, 0, mockEventLocation(t))
+
+	t.Run("Should suggest events", func(t *testing.T) {
+		suggestions := completePuzzle("202", now)
+		if expected := []string{"2022/", "2021/", "2020/"}; !reflect.DeepEqual(suggestions, expected) {
+			t.Errorf("Expected %v, got %v", expected, suggestions)
+		}
+	})
+
+	t.Run("Should suggest unlocked days", func(t *testing.T) {
+		suggestions := completePuzzle("2022/", now)
+		if expected := []string{"2022/1", "2022/2", "2022/3"}; !reflect.DeepEqual(suggestions, expected) {
+			t.Errorf("Expected %v, got %v", expected, suggestions)
+		}
+	})
+
+	t.Run("Should suggest every day of past events", func(t *testing.T) {
+		if suggestions := completePuzzle("2021/", now); len(suggestions) != lastDay {
+			t.Errorf("Expected %d days, got %v", lastDay, suggestions)
+		}
+	})
+
+	t.Run("Should suggest nothing for invalid events", func(t *testing.T) {
+		for _, word := range []string{"2023/", "1999/", "abc/"} {
+			if suggestions := completePuzzle(word, now); len(suggestions) != 0 {
+				t.Errorf("Expected no suggestions for %s, got %v", word, suggestions)
+			}
+		}
+	})
+}
+
+func TestWritingCompletions(t *testing.T) {
+	for _, shell := range completionShells {
+		t.Run("Should complete every command and flag in "+shell, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := execute([]string{"completion", shell}, &stdout, &stderr); code != 0 {
+				t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+			}
+
+			script := stdout.String()
+			for _, cmd := range newCompletionData().Commands {
+				if !strings.Contains(script, cmd.Name) {
+					t.Errorf("Expected %s in the %s script", cmd.Name, shell)
+				}
+				for _, f := range cmd.Flags {
+					if !strings.Contains(script, f.Name) {
+						t.Errorf("Expected -%s in the %s script", f.Name, shell)
+					}
+				}
+			}
+			if !strings.Contains(script, "aoc __complete") {
+				t.Errorf("Expected the %s script to suggest puzzles with aoc __complete", shell)
+			}
+		})
+	}
+
+	t.Run("Should return error for unknown shell", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute([]string{"completion", "tcsh"}, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit code 2, got %d", code)
+		}
+	})
+}
+
+func TestCompletingArguments(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := execute([]string{"__complete", "leaderboard", "ex"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if stdout.String() != "export\n" {
+		t.Errorf("Expected export, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	execute([]string{"help"}, &stdout, &stderr)
+	if strings.Contains(stdout.String(), "__complete") {
+		t.Error("Expected __complete to be hidden")
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const inputPlaceholder = "{input}"
+
+var execCommand = exec.Command
+
+// buildCommand splits the command template into a command, replacing {input} with the input's path.
+// If the template has no {input}, the content is piped to the command's stdin instead.
+func buildCommand(template, inputPath string, content []byte) (cmd *exec.Cmd, err error) {
+	fields := strings.Fields(template)
+	if len(fields) == 0 {
+		return cmd, errors.New("No command to run")
+	}
+
+	usesPath := strings.Contains(template, inputPlaceholder)
+	if usesPath && inputPath == "-" {
+		return cmd, errors.New("Cannot pass {input} to the command when writing the input to stdout")
+	}
+
+	for i, field := range fields {
+		fields[i] = strings.ReplaceAll(field, inputPlaceholder, inputPath)
+	}
+
+	cmd = execCommand(fields[0], fields[1:]...)
+	if !usesPath {
+		cmd.Stdin = bytes.NewReader(content)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd, nil
+}
+
+// runCommand runs the command template against the fetched input, writing its output to stdout,
+// and returns the command's exit code
+func runCommand(template, inputPath string, content []byte, stdout io.Writer) (exitCode int, err error) {
+	return runCommandIn("", template, inputPath, content, stdout)
+}
+
+// runCommandIn runs the command template in dir, see runCommand
+func runCommandIn(dir, template, inputPath string, content []byte, stdout io.Writer) (exitCode int, err error) {
+	cmd, err := buildCommand(template, inputPath, content)
+	if err != nil {
+		return exitCode, err
+	}
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	logger.Debug("running", "command", template, "dir", dir)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// outputOf runs the command template in dir and returns what it writes to stdout
+func outputOf(dir, template, inputPath string, content []byte) (output string, err error) {
+	cmd, err := buildCommand(template, inputPath, content)
+	if err != nil {
+		return output, err
+	}
+
+	var stdout bytes.Buffer
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	logger.Debug("running", "command", template, "dir", dir)
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("%s: %w", template, err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+)
+
+func TestBuildingCommand(t *testing.T) {
+	t.Run("Should replace {input} with the input path", func(t *testing.T) {
+		cmd, err := buildCommand("go run ./day01 {input}", "inputs.txt", []byte("1\n"))
+		if err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+
+		expected := []string{"go", "run", "./day01", "inputs.txt"}
+		if len(cmd.Args) != len(expected) {
+			t.Fatalf("Expected args %v, got %v", expected, cmd.Args)
+		}
+		for i, arg := range expected {
+			if cmd.Args[i] != arg {
+				t.Errorf("Expected args %v, got %v", expected, cmd.Args)
+			}
+		}
+		if cmd.Stdin != nil {
+			t.Error("Should not pipe the input when passing its path")
+		}
+	})
+
+	t.Run("Should pipe the input without {input}", func(t *testing.T) {
+		cmd, err := buildCommand("python3 day01.py", "inputs.txt", []byte("1\n"))
+		if err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+		if cmd.Stdin == nil {
+			t.Error("Should pipe the input to the command")
+		}
+	})
+
+	t.Run("Should return error for {input} when writing to stdout", func(t *testing.T) {
+		if _, err := buildCommand("cat {input}", "-", []byte("1\n")); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Should return error for empty command", func(t *testing.T) {
+		if _, err := buildCommand("  ", "inputs.txt", []byte("1\n")); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestRunningCommand(t *testing.T) {
+	if _, err := exec.LookPath("false"); err != nil {
+		t.Skip("false is not available")
+	}
+
+	exitCode, err := runCommand("false", "-", []byte("1\n"), io.Discard)
+	if err != nil {
+		t.Errorf("Should not have error, got error: %s", err.Error())
+	}
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var sqliteCommand = "sqlite3"
+
+// SolveRow is a single star on a leaderboard, flattened for exporting
+type SolveRow struct {
+	Leaderboard string    `json:"leaderboard"`
+	Year        int       `json:"year"`
+	MemberID    int       `json:"member_id"`
+	Member      string    `json:"member"`
+	Day         int       `json:"day"`
+	Part        int       `json:"part"`
+	SolvedAt    time.Time `json:"solved_at"`
+	Seconds     int64     `json:"seconds"`
+}
+
+var solveColumns = []string{"leaderboard", "year", "member_id", "member", "day", "part", "solved_at", "seconds"}
+
+// flattenLeaderboard returns a row for every star on the leaderboard, in the order they were solved.
+// Seconds are measured from the puzzle unlocking.
+func flattenLeaderboard(leaderboard Leaderboard, id string, est *time.Location) (rows []SolveRow, err error) {
+	year, err := leaderboardYear(leaderboard)
+	if err != nil {
+		return rows, err
+	}
+
+	for _, member := range rankMembers(leaderboard) {
+		for day := 1; day <= lastDay; day++ {
+			for part := 1; part <= 2; part++ {
+				solvedAt, ok := member.Solved(day, part)
+				if !ok {
+					continue
+				}
+				rows = append(rows, SolveRow{
+					Leaderboard: id,
+					Year:        year,
+					MemberID:    member.ID,
+					Member:      member.DisplayName(),
+					Day:         day,
+					Part:        part,
+					SolvedAt:    solvedAt.In(est),
+					Seconds:     int64(solvedAt.Sub(unlockTime(year, day, est)) / time.Second),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].SolvedAt.Before(rows[j].SolvedAt) })
+	return rows, nil
+}
+
+func (r SolveRow) values() []string {
+	return []string{
+		r.Leaderboard,
+		strconv.Itoa(r.Year),
+		strconv.Itoa(r.MemberID),
+		r.Member,
+		strconv.Itoa(r.Day),
+		strconv.Itoa(r.Part),
+		r.SolvedAt.Format(time.RFC3339),
+		strconv.FormatInt(r.Seconds, 10),
+	}
+}
+
+func writeCSV(w io.Writer, rows []SolveRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(solveColumns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(row.values()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeJSONLines(w io.Writer, rows []SolveRow) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sqlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// sqliteScript builds the SQL that records a snapshot and upserts its solves, so a database can
+// accumulate leaderboards across fetches and years
+func sqliteScript(rows []SolveRow, id string, year int, fetchedAt time.Time) string {
+	var script strings.Builder
+	script.WriteString(`BEGIN;
+CREATE TABLE IF NOT EXISTS snapshots (
+	leaderboard TEXT NOT NULL,
+	year INTEGER NOT NULL,
+	fetched_at TEXT NOT NULL,
+	PRIMARY KEY (leaderboard, year, fetched_at)
+);
+CREATE TABLE IF NOT EXISTS solves (
+	leaderboard TEXT NOT NULL,
+	year INTEGER NOT NULL,
+	member_id INTEGER NOT NULL,
+	member TEXT NOT NULL,
+	day INTEGER NOT NULL,
+	part INTEGER NOT NULL,
+	solved_at TEXT NOT NULL,
+	seconds INTEGER NOT NULL,
+	PRIMARY KEY (leaderboard, year, member_id, day, part)
+);
+`)
+	fmt.Fprintf(&script, "INSERT OR IGNORE INTO snapshots VALUES (%s, %d, %s);\n", sqlQuote(id), year, sqlQuote(fetchedAt.Format(time.RFC3339)))
+
+	for _, row := range rows {
+		fmt.Fprintf(&script, "INSERT OR REPLACE INTO solves VALUES (%s, %d, %d, %s, %d, %d, %s, %d);\n",
+			sqlQuote(row.Leaderboard), row.Year, row.MemberID, sqlQuote(row.Member), row.Day, row.Part,
+			sqlQuote(row.SolvedAt.Format(time.RFC3339)), row.Seconds)
+	}
+
+	script.WriteString("COMMIT;\n")
+	return script.String()
+}
+
+// writeSQLite adds the rows to a SQLite database with the sqlite3 command
+func writeSQLite(path string, rows []SolveRow, id string, year int, fetchedAt time.Time) error {
+	cmd := execCommand(sqliteCommand, path)
+	cmd.Stdin = strings.NewReader(sqliteScript(rows, id, year, fetchedAt))
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("Could not write to %s with %s: %s %s", path, sqliteCommand, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// exportLeaderboard writes the leaderboard as csv or jsonl to w, or into the sqlite database at dbPath
+func exportLeaderboard(w io.Writer, leaderboard Leaderboard, id, format, dbPath string, fetchedAt time.Time) error {
+	rows, err := flattenLeaderboard(leaderboard, id, fetchedAt.Location())
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "csv":
+		return writeCSV(w, rows)
+	case "jsonl":
+		return writeJSONLines(w, rows)
+	case "sqlite":
+		year, err := leaderboardYear(leaderboard)
+		if err != nil {
+			return err
+		}
+		return writeSQLite(dbPath, rows, id, year, fetchedAt)
+	}
+	return fmt.Errorf("%s is not an export format, use csv, jsonl or sqlite", format)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlatteningLeaderboard(t *testing.T) {
+	rows, err := flattenLeaderboard(mockLeaderboard(t), "1", mockEventLocation(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 8 {
+		t.Fatalf("Expected a row for each of the 8 stars, got %d", len(rows))
+	}
+
+	first := rows[0]
+	if first.Member != "bob" || first.Day != 1 || first.Part != 1 || first.Seconds != 0 {
+		t.Errorf("Expected bob's day 1 part 1 at unlock to be first, got %+v", first)
+	}
+
+	last := rows[len(rows)-1]
+	if last.Member != "alice" || last.Day != 2 || last.Part != 2 || last.Seconds != 2800 {
+		t.Errorf("Expected alice's day 2 part 2 after 2800 seconds to be last, got %+v", last)
+	}
+}
+
+func TestWritingExports(t *testing.T) {
+	rows, err := flattenLeaderboard(mockLeaderboard(t), "1", mockEventLocation(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should write csv with a header", func(t *testing.T) {
+		var output bytes.Buffer
+		if err := writeCSV(&output, rows); err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		if lines[0] != strings.Join(solveColumns, ",") {
+			t.Errorf("Expected header %v, got %s", solveColumns, lines[0])
+		}
+		if expected := "1,2022,2,bob,1,1,2022-12-01T00:00:00-05:00,0"; lines[1] != expected {
+			t.Errorf("Expected %s, got %s", expected, lines[1])
+		}
+	})
+
+	t.Run("Should write a JSON object per line", func(t *testing.T) {
+		var output bytes.Buffer
+		if err := writeJSONLines(&output, rows); err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		if len(lines) != len(rows) {
+			t.Fatalf("Expected %d lines, got %d", len(rows), len(lines))
+		}
+
+		var row SolveRow
+		if err := json.Unmarshal([]byte(lines[0]), &row); err != nil {
+			t.Fatal(err)
+		}
+		if row.Member != "bob" {
+			t.Errorf("Expected bob, got %s", row.Member)
+		}
+	})
+}
+
+func TestSQLiteScript(t *testing.T) {
+	rows := []SolveRow{{Leaderboard: "1", Year: 2022, MemberID: 1, Member: "o'brien", Day: 1, Part: 1}}
+	script := sqliteScript(rows, "1", 2022, time.Unix(0, 0))
+
+	if !strings.Contains(script, "'o''brien'") {
+		t.Errorf("Expected names to be quoted, got:\n%s", script)
+	}
+}
+
+func TestWritingSQLite(t *testing.T) {
+	if _, err := exec.LookPath(sqliteCommand); err != nil {
+		t.Skip("sqlite3 is not available")
+	}
+
+	path := filepath.Join(t.TempDir(), "leaderboard.db")
+	leaderboard := mockLeaderboard(t)
+	fetchedAt := getNow(t, INSIDE_ADVENT_DATE)
+
+	// exporting twice should add a snapshot without duplicating solves
+	for _, snapshot := range []time.Time{fetchedAt, fetchedAt.Add(time.Hour)} {
+		rows, err := flattenLeaderboard(leaderboard, "1", mockEventLocation(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writeSQLite(path, rows, "1", 2022, snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output, err := exec.Command(sqliteCommand, path, "SELECT COUNT(*) FROM solves; SELECT COUNT(*) FROM snapshots;").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts := strings.Fields(string(output)); len(counts) != 2 || counts[0] != "8" || counts[1] != "2" {
+		t.Errorf("Expected 8 solves and 2 snapshots, got %v", counts)
+	}
+}
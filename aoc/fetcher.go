@@ -3,6 +3,7 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -11,7 +12,12 @@ import (
 	"time"
 )
 
-const firstYear = 2015
+const (
+	firstYear      = 2015
+	lastDay        = 25
+	defaultBaseURL = "https://adventofcode.com"
+	baseURLEnv     = "AOC_BASE_URL"
+)
 
 type httpClient interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -19,27 +25,63 @@ type httpClient interface {
 
 var client httpClient = &http.Client{}
 
-// / validates the input URL based on the ETC/UTC-5 date as that is when puzzles are unlocked
-func validateURL(inputURL string, now time.Time) error {
+// baseURL is where advent of code is, from -base-url or AOC_BASE_URL, so a mirror or a test server can stand in for it.
+// It has no trailing slash.
+func baseURL() string {
+	base := baseURLFlag
+	if base == "" {
+		base = getEnv(baseURLEnv)
+	}
+	if base == "" {
+		base = defaultBaseURL
+	}
+	return strings.TrimSuffix(base, "/")
+}
+
+// checkBaseURL returns error if the base url is not an http or https url, which may have a path prefix
+func checkBaseURL() error {
+	base, err := url.Parse(baseURL())
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" || base.RawQuery != "" || base.Fragment != "" {
+		return usageError(fmt.Sprintf("%s is not a valid base url, ie. %s", baseURL(), defaultBaseURL))
+	}
+	return nil
+}
+
+// puzzlePath splits the path of a url after the base url's path prefix, ie. ["", "2022", "day", "1"]
+func puzzlePath(inputURL string) (parsedPath []string, err error) {
 	parsedURL, err := url.Parse(inputURL)
+	if err != nil {
+		return parsedPath, withCode(codeInvalidPuzzle, err)
+	}
 
+	base, err := url.Parse(baseURL())
 	if err != nil {
-		return err
+		return parsedPath, err
 	}
 
-	if parsedURL.Host != "adventofcode.com" {
-		return fmt.Errorf("%s is not a valid advent of code url", inputURL)
+	prefix := strings.TrimSuffix(base.Path, "/")
+	inBase := prefix == "" || parsedURL.Path == prefix || strings.HasPrefix(parsedURL.Path, prefix+"/")
+	if parsedURL.Host != base.Host || !inBase {
+		return parsedPath, withCode(codeInvalidPuzzle, fmt.Errorf("%s is not a valid advent of code url", inputURL))
 	}
 
-	parsedPath := strings.Split(parsedURL.Path, "/")
+	return strings.Split(strings.TrimPrefix(parsedURL.Path, prefix), "/"), nil
+}
+
+// / validates the input URL based on the ETC/UTC-5 date as that is when puzzles are unlocked
+func validateURL(inputURL string, now time.Time) error {
+	parsedPath, err := puzzlePath(inputURL)
+	if err != nil {
+		return err
+	}
 
 	if len(parsedPath) < 4 {
-		return errors.New("Url did not include a day")
+		return withCode(codeInvalidPuzzle, errors.New("Url did not include a day"))
 	}
 
 	year, err := strconv.Atoi(parsedPath[1])
 	if err != nil {
-		return err
+		return withCode(codeInvalidPuzzle, err)
 	}
 
 	if err := validateYear(year, now); err != nil {
@@ -47,49 +89,91 @@ func validateURL(inputURL string, now time.Time) error {
 	}
 
 	if parsedPath[2] != "day" {
-		return errors.New("Url does not include day")
+		return withCode(codeInvalidPuzzle, errors.New("Url does not include day"))
 	}
 
 	day, err := strconv.Atoi(parsedPath[3])
 	if err != nil {
-		return err
+		return withCode(codeInvalidPuzzle, err)
 	}
-	if day < 1 || day > 25 {
-		return fmt.Errorf("%d is not a valid day", day)
+	if day < 1 || day > lastDay {
+		return withCode(codeInvalidPuzzle, fmt.Errorf("%d is not a valid day", day))
 	}
 
-	currDay := now.Day()
-	if day > currDay {
-		return fmt.Errorf("%d is not yet open", day)
+	if unlockTime(year, day, now.Location()).After(now) {
+		return withCode(codeLocked, fmt.Errorf("%d is not yet open", day))
 	}
 
 	return nil
 }
 
+// parsePuzzleURL validates a puzzle url and returns its year and day
+func parsePuzzleURL(inputURL string, now time.Time) (year, day int, err error) {
+	if err = validateURL(inputURL, now); err != nil {
+		return year, day, err
+	}
+
+	parsedPath, err := puzzlePath(inputURL)
+	if err != nil {
+		return year, day, err
+	}
+
+	year, _ = strconv.Atoi(parsedPath[1])
+	day, _ = strconv.Atoi(parsedPath[3])
+	return year, day, nil
+}
+
 func validateYear(year int, now time.Time) error {
 	currentYear := now.Year()
 	if year < firstYear || year > currentYear {
-		return fmt.Errorf("Invalid year: %d", year)
+		return withCode(codeInvalidPuzzle, fmt.Errorf("Invalid year: %d", year))
 	}
 	currentMonth := now.Month()
 	if year == currentYear && currentMonth != time.December {
-		return errors.New("It is not December yet")
+		return withCode(codeLocked, errors.New("It is not December yet"))
 	}
 
 	return nil
 }
 
+// latestEvent returns the year of the most recent event that has started
+func latestEvent(now time.Time) int {
+	if now.Month() == time.December {
+		return now.Year()
+	}
+	return now.Year() - 1
+}
+
+// eventLocation is ETC/UTC-5, where puzzles are unlocked
+func eventLocation() (*time.Location, error) {
+	return time.LoadLocation("America/New_York")
+}
+
+// eventNow returns the current time in ETC/UTC-5
+func eventNow() (now time.Time, err error) {
+	est, err := eventLocation()
+	if err != nil {
+		return now, err
+	}
+	return clock.Now().In(est), nil
+}
+
+// unlockTime returns when a day's puzzle is unlocked
+func unlockTime(year, day int, est *time.Location) time.Time {
+	return time.Date(year, time.December, day, 0, 0, 0, 0, est)
+}
+
 func checkCookie(cookie http.Cookie) error {
 	if cookie.Name != "session" || cookie.Value == "" {
-		return errors.New("No session cookie")
+		return withCode(codeSession, errors.New("No session cookie"))
 	}
 
 	if !regexp.MustCompile(`^[a-zA-Z0-9]*$`).MatchString(cookie.Value) {
-		return errors.New("Not a valid session cookie")
+		return withCode(codeSession, errors.New("Not a valid session cookie"))
 	}
 
-	if !cookie.Expires.IsZero() && cookie.Expires.Before(time.Now()) {
-		return errors.New("Expired session cookie")
+	if !cookie.Expires.IsZero() && cookie.Expires.Before(clock.Now()) {
+		return withCode(codeSession, errors.New("Expired session cookie"))
 	}
 
 	return nil
@@ -98,7 +182,7 @@ func checkCookie(cookie http.Cookie) error {
 // MakeCookie makes a session cookie from a sessionID
 func makeCookie(sessionID string) (cookie http.Cookie, err error) {
 	if len(sessionID) == 0 {
-		return cookie, errors.New("sessionId must have a value")
+		return cookie, withCode(codeSession, errors.New("sessionId must have a value"))
 	}
 
 	cookie = http.Cookie{
@@ -110,30 +194,79 @@ func makeCookie(sessionID string) (cookie http.Cookie, err error) {
 
 // Fetch fetches input for advent of code url and a user's session cookie
 func fetch(url string, cookie http.Cookie) (res *http.Response, err error) {
-	est, err := time.LoadLocation("America/New_York")
+	today, err := eventNow()
 	if err != nil {
 		return res, err
 	}
 
-	today := time.Now().In(est)
-	if err = validateURL(url, today); err != nil {
+	year, day, err := parsePuzzleURL(url, today)
+	if err != nil {
 		return res, err
 	}
 
+	return get(inputURL(year, day), cookie)
+}
+
+// fetchPage reads an advent of code page, failing for anything but a successful response
+func fetchPage(url string, cookie http.Cookie) (body []byte, err error) {
+	res, err := get(url, cookie)
+	if err != nil {
+		return body, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return body, withCode(statusCode(res.StatusCode), fmt.Errorf("Could not fetch %s: %s", url, res.Status))
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// statusCode returns the error code of an unsuccessful response
+func statusCode(status int) string {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return codeSession
+	case http.StatusTooManyRequests:
+		return codeRateLimited
+	}
+	return codeHTTPStatus
+}
+
+// get requests an advent of code page with the user's session cookie
+func get(url string, cookie http.Cookie) (res *http.Response, err error) {
+	return send("GET", url, nil, cookie)
+}
+
+// post submits a form to advent of code with the user's session cookie
+func post(pageURL string, form url.Values, cookie http.Cookie) (res *http.Response, err error) {
+	return send("POST", pageURL, form, cookie)
+}
+
+func send(method, pageURL string, form url.Values, cookie http.Cookie) (res *http.Response, err error) {
 	if err = checkCookie(cookie); err != nil {
 		return res, err
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprint(url, "/input"), nil)
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequest(method, pageURL, body)
 	if err != nil {
 		return res, err
 	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 
 	req.AddCookie(&cookie)
-	res, err = client.Do(req)
-	if err != nil {
+	res, err = transport().Do(req)
+	// errors of the offline, recording and replay clients keep their own code
+	var coded codedError
+	if errors.As(err, &coded) {
 		return res, err
 	}
-
-	return res, err
+	return res, withCode(codeNetwork, err)
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"testing"
 	"time"
 )
@@ -31,6 +32,94 @@ func TestValidateUrl(t *testing.T) {
 	}
 }
 
+func mockBaseURL(t *testing.T, base string) {
+	baseURLFlag = base
+	t.Cleanup(func() { baseURLFlag = "" })
+}
+
+func TestBaseURL(t *testing.T) {
+	now := getNow(t, INSIDE_ADVENT_DATE)
+
+	t.Run("Should default to advent of code", func(t *testing.T) {
+		if baseURL() != defaultBaseURL {
+			t.Errorf("Expected %s, got %s", defaultBaseURL, baseURL())
+		}
+	})
+
+	t.Run("Should read AOC_BASE_URL", func(t *testing.T) {
+		getEnv = func(key string) string {
+			if key == baseURLEnv {
+				return "http://localhost:8080/"
+			}
+			return ""
+		}
+		t.Cleanup(func() { getEnv = os.Getenv })
+
+		if baseURL() != "http://localhost:8080" {
+			t.Errorf("Expected http://localhost:8080, got %s", baseURL())
+		}
+	})
+
+	t.Run("Should validate urls with the path prefix", func(t *testing.T) {
+		mockBaseURL(t, "http://localhost:8080/mirror")
+
+		year, day, err := parsePuzzleURL("http://localhost:8080/mirror/2022/day/1", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if year != 2022 || day != 1 {
+			t.Errorf("Expected 2022 day 1, got %d day %d", year, day)
+		}
+
+		for _, url := range []string{"https://adventofcode.com/2022/day/1", "http://localhost:8080/2022/day/1", "http://localhost:8080/mirrors/2022/day/1"} {
+			if err := validateURL(url, now); errorCode(err) != codeInvalidPuzzle {
+				t.Errorf("%s should not be a valid url, got %v", url, err)
+			}
+		}
+	})
+
+	t.Run("Should build every url from the base url", func(t *testing.T) {
+		mockBaseURL(t, "http://localhost:8080/mirror")
+
+		for url, expected := range map[string]string{
+			expandURL("2022/1"):       "http://localhost:8080/mirror/2022/day/1",
+			inputURL(2022, 1):         "http://localhost:8080/mirror/2022/day/1/input",
+			answerURL(2022, 1):        "http://localhost:8080/mirror/2022/day/1/answer",
+			leaderboardURL(2022, "1"): "http://localhost:8080/mirror/2022/leaderboard/private/view/1.json",
+			calendarURL(2022):         "http://localhost:8080/mirror/2022",
+			selfLeaderboardURL(2022):  "http://localhost:8080/mirror/2022/leaderboard/self",
+		} {
+			if url != expected {
+				t.Errorf("Expected %s, got %s", expected, url)
+			}
+		}
+	})
+
+	t.Run("Should fetch from the base url", func(t *testing.T) {
+		mockBaseURL(t, "http://localhost:8080/mirror")
+		mock := &mockPageClient{pages: map[string]string{"/mirror/2022/day/1/input": "1\n"}}
+		client = mock
+		cookie := http.Cookie{Name: "session", Value: "abc123"}
+
+		res, err := fetch(expandURL("2022/1"), cookie)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != 200 {
+			t.Errorf("Expected the input from the mirror, got %d", res.StatusCode)
+		}
+	})
+
+	t.Run("Should return error for invalid base urls", func(t *testing.T) {
+		for _, base := range []string{"localhost:8080", "ftp://localhost", "http://", "http://localhost?x=1"} {
+			mockBaseURL(t, base)
+			if err := checkBaseURL(); errorCode(err) != codeUsage {
+				t.Errorf("%s should not be a valid base url, got %v", base, err)
+			}
+		}
+	})
+}
+
 func TestErrorIfNotUrl(t *testing.T) {
 	url := "123"
 	now := getNow(t, INSIDE_ADVENT_DATE)
@@ -120,12 +209,29 @@ func TestErrorIfDayTooHigh(t *testing.T) {
 }
 
 func TestErrorIfDayNotYetOpen(t *testing.T) {
-	url := "http://adventofcode.com/2021/day/"
+	url := "http://adventofcode.com/2022/day/"
 	day := 2
 	now := getNow(t, INSIDE_ADVENT_DATE)
 
-	if err := validateURL(fmt.Sprintf("%s%d", url, day), now); err == nil {
-		t.Errorf("%d should not be a valid day", day)
+	if err := validateURL(fmt.Sprintf("%s%d", url, day), now); errorCode(err) != codeLocked {
+		t.Errorf("%d should not be open yet, got %v", day, err)
+	}
+}
+
+func TestDaysOfPastEventsAreOpen(t *testing.T) {
+	tests := []struct {
+		url string
+		now string
+	}{
+		{"http://adventofcode.com/2021/day/2", INSIDE_ADVENT_DATE},
+		{"http://adventofcode.com/2022/day/25", OUTSIDE_ADVENT_DATE},
+		{"http://adventofcode.com/2022/day/25", "2023-10-19 12:00:00"},
+	}
+
+	for _, test := range tests {
+		if err := validateURL(test.url, getNow(t, test.now)); err != nil {
+			t.Errorf("%s should be open on %s, got error: %s", test.url, test.now, err.Error())
+		}
 	}
 }
 
@@ -157,6 +263,20 @@ func TestErrorOnCurrentYearEarlyMonth(t *testing.T) {
 	}
 }
 
+func TestLatestEvent(t *testing.T) {
+	t.Run("Should be the current year in December", func(t *testing.T) {
+		if year := latestEvent(getNow(t, INSIDE_ADVENT_DATE)); year != 2022 {
+			t.Errorf("Expected 2022, got %d", year)
+		}
+	})
+
+	t.Run("Should be last year before December", func(t *testing.T) {
+		if year := latestEvent(getNow(t, OUTSIDE_ADVENT_DATE)); year != 2022 {
+			t.Errorf("Expected 2022, got %d", year)
+		}
+	})
+}
+
 // Tests for Cookie checker
 func TestValidCookie(t *testing.T) {
 	cookie := http.Cookie{
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// userPattern is the header of advent of code pages that shows who is logged in
+	userPattern            = regexp.MustCompile(`(?s)<div class="user">.*?</div>`)
+	leaderboardPathPattern = regexp.MustCompile(`/leaderboard/private/view/\d+\.json$`)
+	// answerPattern is an accepted answer on a puzzle page, answers differ by input so they identify the account
+	answerPattern = regexp.MustCompile(`(Your puzzle answer was <code>)[^<]*(</code>)`)
+)
+
+// fixture is a response of advent of code saved with -record, without anything that identifies the user
+type fixture struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// fixturePath returns the path of a request relative to the base url, so fixtures replay under any base url
+func fixturePath(u *url.URL) string {
+	prefix := ""
+	if base, err := url.Parse(baseURL()); err == nil {
+		prefix = strings.TrimSuffix(base.Path, "/")
+	}
+	return strings.TrimPrefix(u.Path, prefix)
+}
+
+// fixtureName is the file name of the nth fixture of a request, ie. GET_2022_day_1.json and then GET_2022_day_1.2.json
+func fixtureName(method, path string, n int) string {
+	name := method + "_" + strings.ReplaceAll(strings.Trim(strings.TrimSuffix(path, ".json"), "/"), "/", "_")
+	if n > 1 {
+		return fmt.Sprintf("%s.%d.json", name, n)
+	}
+	return name + ".json"
+}
+
+// scrubBody removes what identifies the user from a response: inputs, answers, the name in the page header,
+// and the ids and names on leaderboards
+func scrubBody(path string, body []byte) ([]byte, error) {
+	if strings.HasSuffix(path, "/input") {
+		return []byte(redacted + "\n"), nil
+	}
+
+	var leaderboard map[string]interface{}
+	// leaderboards that cannot be viewed redirect to a page instead
+	if leaderboardPathPattern.MatchString(path) && json.Unmarshal(body, &leaderboard) == nil {
+		return json.MarshalIndent(scrubLeaderboard(leaderboard), "", "  ")
+	}
+
+	body = userPattern.ReplaceAll(body, []byte(`<div class="user">`+redacted+`</div>`))
+	return answerPattern.ReplaceAll(body, []byte("${1}"+redacted+"${2}")), nil
+}
+
+// scrubLeaderboard numbers the members from 1 in the order of their ids and names them after their new id
+func scrubLeaderboard(leaderboard map[string]interface{}) map[string]interface{} {
+	members, _ := leaderboard["members"].(map[string]interface{})
+
+	ids := make([]string, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+
+	renumbered := map[string]interface{}{}
+	newIDs := map[string]int{}
+	for i, id := range ids {
+		newIDs[id] = i + 1
+		member, ok := members[id].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		member["id"] = i + 1
+		if member["name"] != nil {
+			member["name"] = fmt.Sprintf("member %d", i+1)
+		}
+		renumbered[strconv.Itoa(i+1)] = member
+	}
+	leaderboard["members"] = renumbered
+
+	// the owner is a member of their own leaderboard, 0 if they are not in it
+	if owner, ok := leaderboard["owner_id"].(float64); ok {
+		leaderboard["owner_id"] = newIDs[strconv.FormatFloat(owner, 'f', -1, 64)]
+	}
+	return leaderboard
+}
+
+// recordingClient saves every response of advent of code in dir, it never saves the request's cookies or the response's headers
+type recordingClient struct {
+	dir  string
+	next httpClient
+}
+
+func (c recordingClient) Do(req *http.Request) (*http.Response, error) {
+	res, err := c.next.Do(req)
+	if err != nil {
+		return res, err
+	}
+	if base, err := url.Parse(baseURL()); err != nil || req.URL.Host != base.Host {
+		return res, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return res, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := c.save(req.Method, fixturePath(req.URL), res.StatusCode, res.Header.Get("Content-Type"), body); err != nil {
+		return res, withCode(codeIO, err)
+	}
+	return res, nil
+}
+
+func (c recordingClient) save(method, path string, status int, contentType string, body []byte) error {
+	scrubbed, err := scrubBody(path, body)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(fixture{Method: method, Path: path, Status: status, ContentType: contentType, Body: string(scrubbed)}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+
+	// a request made again is saved next to the first one, so replaying serves the responses in order
+	for n := 1; ; n++ {
+		file, err := os.OpenFile(filepath.Join(c.dir, fixtureName(method, path, n)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = file.Write(append(content, '\n'))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+}
+
+// replayClient serves the fixtures saved in dir instead of sending requests.
+// A request made again gets the next fixture saved for it, and the last one once they run out.
+type replayClient struct {
+	dir    string
+	served map[string]int
+}
+
+func newReplayClient(dir string) *replayClient {
+	return &replayClient{dir: dir, served: map[string]int{}}
+}
+
+func (c *replayClient) Do(req *http.Request) (*http.Response, error) {
+	path := fixturePath(req.URL)
+	key := fixtureName(req.Method, path, 1)
+
+	n := c.served[key] + 1
+	content, err := readFile(filepath.Join(c.dir, fixtureName(req.Method, path, n)))
+	if errors.Is(err, os.ErrNotExist) && n > 1 {
+		n--
+		content, err = readFile(filepath.Join(c.dir, fixtureName(req.Method, path, n)))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, withCode(codeNotFound, fmt.Errorf("No fixture for %s %s in %s", req.Method, path, c.dir))
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.served[key] = n
+
+	var saved fixture
+	if err := json.Unmarshal(content, &saved); err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	if saved.ContentType != "" {
+		header.Set("Content-Type", saved.ContentType)
+	}
+	return &http.Response{
+		StatusCode: saved.Status,
+		Status:     fmt.Sprintf("%d %s", saved.Status, http.StatusText(saved.Status)),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(saved.Body)),
+		Request:    req,
+	}, nil
+}
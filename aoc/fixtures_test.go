@@ -0,0 +1,176 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/streakinthesky/adventofcode-fetcher/aoc/aoctest"
+)
+
+const fixturesDir = "testdata/fixtures"
+
+func TestRecordingFixtures(t *testing.T) {
+	mockCacheDir(t)
+	dir := t.TempDir()
+	client = &http.Client{}
+	cookie := http.Cookie{Name: "session", Value: aoctest.DefaultSession}
+
+	server := aoctest.NewServer(aoctest.NewClock(time.Date(2022, time.December, 5, 0, 0, 0, 0, aoctest.EventLocation)))
+	t.Cleanup(server.Close)
+	server.SetInput(2022, 5, "secret input\n")
+	server.SetPuzzle(2022, 5, `<header><div class="user">alice <span class="star-count">8*</span></div></header>`+mockPuzzlePage)
+	server.SetLeaderboard(2022, "1", mockLeaderboardJSON)
+	mockBaseURL(t, server.URL)
+	recordFlag = dir
+	t.Cleanup(func() { recordFlag = "" })
+
+	if _, err := fetchPage(inputURL(2022, 5), cookie); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fetchPuzzle(2022, 5, cookie); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := fetchLeaderboard(2022, "1", cookie); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Should save every response", func(t *testing.T) {
+		for _, name := range []string{"GET_2022_day_5_input.json", "GET_2022_day_5.json", "GET_2022_leaderboard_private_view_1.json", "GET_2022_leaderboard_private_view_1.2.json"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				t.Errorf("Expected %s to be saved, got error: %s", name, err.Error())
+			}
+		}
+	})
+
+	t.Run("Should scrub personal data", func(t *testing.T) {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, secret := range []string{aoctest.DefaultSession, "secret input", "alice", "bob"} {
+				if strings.Contains(string(content), secret) {
+					t.Errorf("Expected %s to be scrubbed from %s", secret, filepath.Base(file))
+				}
+			}
+		}
+	})
+
+	t.Run("Should replay what was recorded", func(t *testing.T) {
+		recordFlag = ""
+		client = newReplayClient(dir)
+		puzzle, err := fetchPuzzle(2022, 5, cookie)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if puzzle.ExampleAnswer != "CMZ" {
+			t.Errorf("Expected example answer CMZ, got %s", puzzle.ExampleAnswer)
+		}
+	})
+}
+
+func TestScrubbingBodies(t *testing.T) {
+	t.Run("Should renumber leaderboard members", func(t *testing.T) {
+		body := `{"owner_id": 2073, "event": "2022", "members": {"2073": {"id": 2073, "name": "alice"}, "815": {"id": 815, "name": null}}}`
+		scrubbed, err := scrubBody("/2022/leaderboard/private/view/2073.json", []byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"2073", "815", "alice"} {
+			if strings.Contains(string(scrubbed), secret) {
+				t.Errorf("Expected %s to be scrubbed from %s", secret, scrubbed)
+			}
+		}
+
+		leaderboard, err := parseLeaderboard(scrubbed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if leaderboard.OwnerID != 2 || leaderboard.Members["1"].ID != 1 || leaderboard.Members["2"].Name != "member 2" {
+			t.Errorf("Unexpected leaderboard %s", scrubbed)
+		}
+	})
+
+	t.Run("Should redact answers", func(t *testing.T) {
+		page := `<p>Your puzzle answer was <code>QNHWJVJZW</code>.</p>`
+		scrubbed, _ := scrubBody("/2022/day/5", []byte(page))
+		if string(scrubbed) != `<p>Your puzzle answer was <code>`+redacted+`</code>.</p>` {
+			t.Errorf("Expected the answer to be redacted, got %s", scrubbed)
+		}
+	})
+}
+
+func TestReplayingFixtures(t *testing.T) {
+	cookie := http.Cookie{Name: "session", Value: "abc123"}
+
+	t.Run("Should parse a puzzle page", func(t *testing.T) {
+		mockCacheDir(t)
+		client = newReplayClient(fixturesDir)
+
+		puzzle, err := fetchPuzzle(2022, 5, cookie)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if puzzle.Title != "Day 5: Supply Stacks" {
+			t.Errorf("Expected title Day 5: Supply Stacks, got %s", puzzle.Title)
+		}
+		if !strings.HasPrefix(puzzle.Example, "    [D]") || !strings.HasSuffix(puzzle.Example, "move 1 from 1 to 2\n") {
+			t.Errorf("Unexpected example %q", puzzle.Example)
+		}
+		if puzzle.ExampleAnswer != "CMZ" {
+			t.Errorf("Expected example answer CMZ, got %s", puzzle.ExampleAnswer)
+		}
+	})
+
+	t.Run("Should parse verdicts in order", func(t *testing.T) {
+		readFile = os.ReadFile
+		client = newReplayClient(fixturesDir)
+
+		for _, expected := range []Verdict{wrongVerdict, tooRecentVerdict, correctVerdict, wrongLevelVerdict, wrongLevelVerdict} {
+			verdict, message, err := submitAnswer(PartKey{Year: 2022, Day: 5, Part: 1}, "CMZ", cookie)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if verdict != expected {
+				t.Errorf("Expected verdict %d, got %d: %s", expected, verdict, message)
+			}
+			if strings.Contains(message, "<") {
+				t.Errorf("Expected the message without markup, got %q", message)
+			}
+		}
+	})
+
+	t.Run("Should parse a leaderboard", func(t *testing.T) {
+		readFile = os.ReadFile
+		client = newReplayClient(fixturesDir)
+
+		body, err := fetchLeaderboard(2022, "1", cookie)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaderboard, err := parseLeaderboard(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		members := rankMembers(leaderboard)
+		if len(members) != 2 || members[0].DisplayName() != "member 1" || members[1].DisplayName() != "(anonymous user #2)" {
+			t.Errorf("Unexpected members %v", members)
+		}
+	})
+
+	t.Run("Should return error without a fixture", func(t *testing.T) {
+		readFile = os.ReadFile
+		client = newReplayClient(fixturesDir)
+
+		if _, err := fetchPage(puzzleURL(2022, 6), cookie); errorCode(err) != codeNotFound {
+			t.Errorf("Expected a not found error, got %v", err)
+		}
+	})
+}
@@ -1,3 +1,3 @@
 module github.com/streakinthesky/adventofcode-fetcher/aoc
 
-go 1.19
+go 1.21
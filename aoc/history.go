@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Snapshot is a leaderboard as it was when it was fetched
+type Snapshot struct {
+	FetchedAt   time.Time   `json:"fetched_at"`
+	Leaderboard Leaderboard `json:"leaderboard"`
+}
+
+// ReplayStar is a star in the race for a day
+type ReplayStar struct {
+	Member   Member        `json:"member"`
+	Part     int           `json:"part"`
+	Position int           `json:"position"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+func snapshotDir(year int, id string) (string, error) {
+	return cachePath("history", fmt.Sprintf("%d-%s", year, id))
+}
+
+// saveSnapshot keeps every fetched leaderboard, named by when it was fetched
+func saveSnapshot(year int, id string, body []byte, fetchedAt time.Time) error {
+	dir, err := snapshotDir(year, id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, fmt.Sprintf("%d.json", fetchedAt.Unix())), body, 0o644)
+}
+
+// loadSnapshots reads the saved snapshots of a leaderboard, oldest first
+func loadSnapshots(year int, id string) (snapshots []Snapshot, err error) {
+	dir, err := snapshotDir(year, id)
+	if err != nil {
+		return snapshots, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return snapshots, err
+	}
+
+	for _, entry := range entries {
+		fetchedAt, err := strconv.ParseInt(strings.TrimSuffix(entry.Name(), ".json"), 10, 64)
+		if entry.IsDir() || err != nil {
+			continue
+		}
+
+		body, err := readFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return snapshots, err
+		}
+
+		leaderboard, err := parseLeaderboard(body)
+		if err != nil {
+			return snapshots, fmt.Errorf("Snapshot %s: %w", entry.Name(), err)
+		}
+		snapshots = append(snapshots, Snapshot{FetchedAt: time.Unix(fetchedAt, 0), Leaderboard: leaderboard})
+	}
+
+	if len(snapshots) == 0 {
+		return snapshots, fmt.Errorf("No snapshots of leaderboard %s for %d, fetch it with aoc leaderboard first", id, year)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].FetchedAt.Before(snapshots[j].FetchedAt) })
+	return snapshots, nil
+}
+
+// mergeMembers collects every member seen in any snapshot, with all the stars they have been seen with
+func mergeMembers(snapshots []Snapshot) map[int]Member {
+	members := map[int]Member{}
+	for _, snapshot := range snapshots {
+		for _, member := range snapshot.Leaderboard.Members {
+			merged, ok := members[member.ID]
+			if !ok {
+				merged = member
+				merged.CompletionDayLevel = map[string]map[string]Star{}
+			}
+			merged.Name = member.Name
+
+			for day, parts := range member.CompletionDayLevel {
+				if merged.CompletionDayLevel[day] == nil {
+					merged.CompletionDayLevel[day] = map[string]Star{}
+				}
+				for part, star := range parts {
+					merged.CompletionDayLevel[day][part] = star
+				}
+			}
+			members[member.ID] = merged
+		}
+	}
+	return members
+}
+
+// renderRankHistory writes the rank of every member at each snapshot, members ordered by their latest rank
+func renderRankHistory(w io.Writer, snapshots []Snapshot) error {
+	latest := rankMembers(snapshots[len(snapshots)-1].Leaderboard)
+	ranks := make([]map[int]int, len(snapshots))
+	for i, snapshot := range snapshots {
+		ranks[i] = memberRanks(snapshot.Leaderboard)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"Fetched"}
+	for _, member := range latest {
+		header = append(header, member.DisplayName())
+	}
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+
+	for i, snapshot := range snapshots {
+		row := []string{snapshot.FetchedAt.Format("2006-01-02 15:04")}
+		for _, member := range latest {
+			rank, ok := ranks[i][member.ID]
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, strconv.Itoa(rank))
+		}
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+
+	return table.Flush()
+}
+
+// replayDay returns every star for the day from all the snapshots, in the order they were solved
+func replayDay(snapshots []Snapshot, day int, est *time.Location) (stars []ReplayStar, err error) {
+	year, err := leaderboardYear(snapshots[len(snapshots)-1].Leaderboard)
+	if err != nil {
+		return stars, err
+	}
+
+	for _, member := range mergeMembers(snapshots) {
+		for part := 1; part <= 2; part++ {
+			if duration, ok := solveDuration(member, year, day, part, est); ok {
+				stars = append(stars, ReplayStar{Member: member, Part: part, Duration: duration})
+			}
+		}
+	}
+
+	sort.Slice(stars, func(i, j int) bool {
+		if stars[i].Duration != stars[j].Duration {
+			return stars[i].Duration < stars[j].Duration
+		}
+		return stars[i].Member.ID < stars[j].Member.ID
+	})
+
+	positions := map[int]int{}
+	for i := range stars {
+		positions[stars[i].Part]++
+		stars[i].Position = positions[stars[i].Part]
+	}
+	return stars, nil
+}
+
+// renderReplay writes the race for a day, one star at a time
+func renderReplay(w io.Writer, stars []ReplayStar) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Time\tPart\tPosition\tName")
+
+	for _, star := range stars {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%s\n", formatDuration(star.Duration), star.Part, star.Position, star.Member.DisplayName())
+	}
+
+	return table.Flush()
+}
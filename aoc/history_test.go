@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func saveMockSnapshots(t *testing.T) {
+	mockCacheDir(t)
+	next, _ := json.Marshal(mockNextLeaderboard(t))
+
+	if err := saveSnapshot(2022, "1", []byte(mockLeaderboardJSON), time.Unix(1669960050, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveSnapshot(2022, "1", next, time.Unix(1669961000, 0)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadingSnapshots(t *testing.T) {
+	t.Run("Should load snapshots oldest first", func(t *testing.T) {
+		saveMockSnapshots(t)
+
+		snapshots, err := loadSnapshots(2022, "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(snapshots) != 2 {
+			t.Fatalf("Expected 2 snapshots, got %d", len(snapshots))
+		}
+		if !snapshots[0].FetchedAt.Before(snapshots[1].FetchedAt) {
+			t.Error("Expected snapshots to be ordered by when they were fetched")
+		}
+	})
+
+	t.Run("Should return error without snapshots", func(t *testing.T) {
+		mockCacheDir(t)
+
+		if _, err := loadSnapshots(2022, "1"); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestRenderingRankHistory(t *testing.T) {
+	saveMockSnapshots(t)
+	snapshots, err := loadSnapshots(2022, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	if err := renderRankHistory(&output, snapshots); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and a row per snapshot, got:\n%s", output.String())
+	}
+	if header := strings.Fields(lines[0]); header[1] != "bob" || header[2] != "alice" {
+		t.Errorf("Expected members in their latest order, got %s", lines[0])
+	}
+	if ranks := strings.Fields(lines[1]); ranks[2] != "2" || ranks[3] != "1" || ranks[len(ranks)-1] != "-" {
+		t.Errorf("Expected bob 2nd, alice 1st and dave missing in the first snapshot, got %s", lines[1])
+	}
+}
+
+func TestReplayingDay(t *testing.T) {
+	saveMockSnapshots(t)
+	snapshots, err := loadSnapshots(2022, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stars, err := replayDay(snapshots, 2, mockEventLocation(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		name     string
+		part     int
+		position int
+	}{
+		{name: "alice", part: 1, position: 1},
+		{name: "bob", part: 1, position: 2},
+		{name: "alice", part: 2, position: 1},
+		{name: "bob", part: 2, position: 2},
+	}
+	if len(stars) != len(expected) {
+		t.Fatalf("Expected %d stars, got %d", len(expected), len(stars))
+	}
+	for i, star := range expected {
+		if stars[i].Member.Name != star.name || stars[i].Part != star.part || stars[i].Position != star.position {
+			t.Errorf("Expected %s part %d in position %d, got %+v", star.name, star.part, star.position, stars[i])
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Leaderboard is a private leaderboard as served by /{year}/leaderboard/private/view/{id}.json
+type Leaderboard struct {
+	OwnerID int               `json:"owner_id"`
+	Event   string            `json:"event"`
+	Members map[string]Member `json:"members"`
+}
+
+// Member is a single user on a private leaderboard
+type Member struct {
+	ID                 int                        `json:"id"`
+	Name               string                     `json:"name"`
+	Stars              int                        `json:"stars"`
+	LocalScore         int                        `json:"local_score"`
+	GlobalScore        int                        `json:"global_score"`
+	LastStarTs         int64                      `json:"last_star_ts"`
+	CompletionDayLevel map[string]map[string]Star `json:"completion_day_level"`
+}
+
+// Star is the time a member solved a part of a day's puzzle
+type Star struct {
+	GetStarTs int64 `json:"get_star_ts"`
+	StarIndex int64 `json:"star_index"`
+}
+
+// DisplayName returns the member's name, or the placeholder the site uses for anonymous users
+func (m Member) DisplayName() string {
+	if m.Name == "" {
+		return fmt.Sprintf("(anonymous user #%d)", m.ID)
+	}
+	return m.Name
+}
+
+// Solved returns when the member solved the part of the day, if they have
+func (m Member) Solved(day, part int) (solvedAt time.Time, ok bool) {
+	star, ok := m.CompletionDayLevel[strconv.Itoa(day)][strconv.Itoa(part)]
+	if !ok {
+		return solvedAt, false
+	}
+	return time.Unix(star.GetStarTs, 0), true
+}
+
+func leaderboardURL(year int, id string) string {
+	return fmt.Sprintf("%s/%d/leaderboard/private/view/%s.json", baseURL(), year, id)
+}
+
+// validateLeaderboardID makes sure the id is only digits, as it is part of urls and of paths in the cache directory
+func validateLeaderboardID(id string) error {
+	if id == "" || strings.Trim(id, "0123456789") != "" {
+		return usageError(fmt.Sprintf("%s is not a valid leaderboard id", id))
+	}
+	return nil
+}
+
+// fetchLeaderboard fetches the raw JSON of a private leaderboard
+func fetchLeaderboard(year int, id string, cookie http.Cookie) (body []byte, err error) {
+	if err = validateLeaderboardID(id); err != nil {
+		return body, err
+	}
+
+	return fetchPage(leaderboardURL(year, id), cookie)
+}
+
+// parseLeaderboard decodes leaderboard JSON
+func parseLeaderboard(body []byte) (leaderboard Leaderboard, err error) {
+	if err = json.Unmarshal(body, &leaderboard); err != nil {
+		return leaderboard, errors.New("Leaderboard is not valid JSON, is the session cookie a member of it?")
+	}
+	return leaderboard, nil
+}
+
+// rankMembers sorts members the way the site does: by local score, then stars, then who got there first
+func rankMembers(leaderboard Leaderboard) []Member {
+	members := make([]Member, 0, len(leaderboard.Members))
+	for _, member := range leaderboard.Members {
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if a.LocalScore != b.LocalScore {
+			return a.LocalScore > b.LocalScore
+		}
+		if a.Stars != b.Stars {
+			return a.Stars > b.Stars
+		}
+		if a.LastStarTs != b.LastStarTs {
+			return a.LastStarTs < b.LastStarTs
+		}
+		return a.ID < b.ID
+	})
+
+	return members
+}
+
+// starSymbol draws * for both parts of a day, + for only the first and . for neither
+func starSymbol(stars int) byte {
+	switch stars {
+	case 2:
+		return '*'
+	case 1:
+		return '+'
+	}
+	return '.'
+}
+
+// starRow draws a member's progress through the event
+func starRow(member Member) string {
+	var row strings.Builder
+	for day := 1; day <= lastDay; day++ {
+		stars := 0
+		for part := 1; part <= 2; part++ {
+			if _, ok := member.Solved(day, part); ok {
+				stars++
+			}
+		}
+		row.WriteByte(starSymbol(stars))
+	}
+	return row.String()
+}
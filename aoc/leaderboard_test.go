@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// leaderboard for 2022 where alice solved days 1 and 2, bob solved day 1 and part 1 of day 2, and carol only part 1 of day 1
+const mockLeaderboardJSON = `{
+	"owner_id": 1,
+	"event": "2022",
+	"members": {
+		"1": {
+			"id": 1,
+			"name": "alice",
+			"stars": 4,
+			"local_score": 11,
+			"global_score": 0,
+			"last_star_ts": 1669960000,
+			"completion_day_level": {
+				"1": {"1": {"get_star_ts": 1669871100, "star_index": 1}, "2": {"get_star_ts": 1669871400, "star_index": 2}},
+				"2": {"1": {"get_star_ts": 1669957800, "star_index": 10}, "2": {"get_star_ts": 1669960000, "star_index": 12}}
+			}
+		},
+		"2": {
+			"id": 2,
+			"name": "bob",
+			"stars": 3,
+			"local_score": 8,
+			"global_score": 0,
+			"last_star_ts": 1669958400,
+			"completion_day_level": {
+				"1": {"1": {"get_star_ts": 1669870800, "star_index": 0}, "2": {"get_star_ts": 1669872600, "star_index": 3}},
+				"2": {"1": {"get_star_ts": 1669958400, "star_index": 11}}
+			}
+		},
+		"3": {
+			"id": 3,
+			"name": null,
+			"stars": 1,
+			"local_score": 1,
+			"global_score": 0,
+			"last_star_ts": 1669900000,
+			"completion_day_level": {
+				"1": {"1": {"get_star_ts": 1669900000, "star_index": 5}}
+			}
+		}
+	}
+}`
+
+func mockLeaderboard(t *testing.T) Leaderboard {
+	leaderboard, err := parseLeaderboard([]byte(mockLeaderboardJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaderboard
+}
+
+func mockBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
+
+func TestParsingLeaderboard(t *testing.T) {
+	t.Run("Should parse members", func(t *testing.T) {
+		leaderboard := mockLeaderboard(t)
+
+		if len(leaderboard.Members) != 3 {
+			t.Errorf("Expected 3 members, got %d", len(leaderboard.Members))
+		}
+		if leaderboard.Members["1"].Name != "alice" {
+			t.Errorf("Expected member 1 to be alice, got %s", leaderboard.Members["1"].Name)
+		}
+	})
+
+	t.Run("Should parse star timestamps", func(t *testing.T) {
+		leaderboard := mockLeaderboard(t)
+
+		solvedAt, ok := leaderboard.Members["2"].Solved(1, 2)
+		if !ok {
+			t.Fatal("Expected bob to have solved day 1 part 2")
+		}
+		if solvedAt.Unix() != 1669872600 {
+			t.Errorf("Expected solve time 1669872600, got %d", solvedAt.Unix())
+		}
+
+		if _, ok := leaderboard.Members["2"].Solved(2, 2); ok {
+			t.Error("Expected bob not to have solved day 2 part 2")
+		}
+	})
+
+	t.Run("Should name anonymous users", func(t *testing.T) {
+		leaderboard := mockLeaderboard(t)
+		expected := "(anonymous user #3)"
+
+		if name := leaderboard.Members["3"].DisplayName(); name != expected {
+			t.Errorf("Expected %s, got %s", expected, name)
+		}
+	})
+
+	t.Run("Should return error for invalid JSON", func(t *testing.T) {
+		if _, err := parseLeaderboard([]byte("<html></html>")); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestRankingMembers(t *testing.T) {
+	members := rankMembers(mockLeaderboard(t))
+
+	expected := []int{1, 2, 3}
+	for i, id := range expected {
+		if members[i].ID != id {
+			t.Errorf("Expected member %d at rank %d, got %d", id, i+1, members[i].ID)
+		}
+	}
+}
+
+func TestRenderingLeaderboard(t *testing.T) {
+	var output bytes.Buffer
+	if err := renderStandings(&output, localStandings(mockLeaderboard(t))); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a header and 3 rows, got:\n%s", output.String())
+	}
+	if !strings.Contains(lines[2], "*+.......................") || !strings.Contains(lines[2], "bob") {
+		t.Errorf("Expected bob's row to show his stars, got %s", lines[2])
+	}
+}
+
+func TestFetchingLeaderboard(t *testing.T) {
+	cookie := http.Cookie{
+		Name:  "session",
+		Value: "abc123",
+	}
+
+	t.Run("Should return leaderboard JSON", func(t *testing.T) {
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+
+		body, err := fetchLeaderboard(2022, "1", cookie)
+		if err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+		if string(body) != mockLeaderboardJSON {
+			t.Error("Expected the leaderboard JSON")
+		}
+	})
+
+	t.Run("Should return error for unsuccessful status", func(t *testing.T) {
+		client = &mockClient{res: http.Response{StatusCode: 404, Status: "404 Not Found", Body: mockBody("")}}
+
+		if _, err := fetchLeaderboard(2022, "1", cookie); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Should return error for invalid id", func(t *testing.T) {
+		client = &mockClient{}
+
+		if _, err := fetchLeaderboard(2022, "../self", cookie); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
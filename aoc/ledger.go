@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Ledger records the answers that were accepted or rejected for each part
+type Ledger struct {
+	Answers map[string]LedgerEntry `json:"answers"`
+}
+
+// LedgerEntry is what is known about the answer to a part
+type LedgerEntry struct {
+	Correct string   `json:"correct,omitempty"`
+	Wrong   []string `json:"wrong,omitempty"`
+}
+
+// PartKey identifies a part of a day's puzzle
+type PartKey struct {
+	Year int `json:"year"`
+	Day  int `json:"day"`
+	Part int `json:"part"`
+}
+
+func (k PartKey) String() string {
+	return fmt.Sprintf("%d/%d/%d", k.Year, k.Day, k.Part)
+}
+
+func parsePartKey(key string) (partKey PartKey, err error) {
+	if _, err := fmt.Sscanf(key, "%d/%d/%d", &partKey.Year, &partKey.Day, &partKey.Part); err != nil {
+		return partKey, fmt.Errorf("%s is not a valid ledger entry", key)
+	}
+	return partKey, nil
+}
+
+// ledgerPath returns path, or the ledger in the user's config directory when path is empty
+func ledgerPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+
+	configDir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "aoc", "ledger.json"), nil
+}
+
+// loadLedger reads the ledger, which is empty if it has never been written
+func loadLedger(path string) (ledger Ledger, err error) {
+	ledger.Answers = map[string]LedgerEntry{}
+
+	content, err := readFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ledger, nil
+	}
+	if err != nil {
+		return ledger, err
+	}
+
+	if err := json.Unmarshal(content, &ledger); err != nil {
+		return ledger, fmt.Errorf("%s: %w", path, err)
+	}
+	if ledger.Answers == nil {
+		ledger.Answers = map[string]LedgerEntry{}
+	}
+	return ledger, nil
+}
+
+func saveLedger(path string, ledger Ledger) error {
+	content, err := json.MarshalIndent(ledger, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// Entry returns what is known about the answer to a part
+func (l Ledger) Entry(key PartKey) LedgerEntry {
+	return l.Answers[key.String()]
+}
+
+// IsWrong returns whether the answer has already been rejected
+func (e LedgerEntry) IsWrong(answer string) bool {
+	for _, wrong := range e.Wrong {
+		if wrong == answer {
+			return true
+		}
+	}
+	return false
+}
+
+// Record adds the verdict for an answer to the ledger
+func (l Ledger) Record(key PartKey, answer string, correct bool) {
+	entry := l.Entry(key)
+	if correct {
+		entry.Correct = answer
+	} else if !entry.IsWrong(answer) {
+		entry.Wrong = append(entry.Wrong, answer)
+	}
+	l.Answers[key.String()] = entry
+}
+
+// Accepted returns every part with a correct answer, in order
+func (l Ledger) Accepted() (keys []PartKey, err error) {
+	for key, entry := range l.Answers {
+		if entry.Correct == "" {
+			continue
+		}
+		partKey, err := parsePartKey(key)
+		if err != nil {
+			return keys, err
+		}
+		keys = append(keys, partKey)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		return a.Part < b.Part
+	})
+	return keys, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLedger(t *testing.T) {
+	key := PartKey{Year: 2022, Day: 5, Part: 1}
+
+	t.Run("Should be empty when it does not exist", func(t *testing.T) {
+		readFile = os.ReadFile
+
+		ledger, err := loadLedger(filepath.Join(t.TempDir(), "ledger.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ledger.Answers) != 0 {
+			t.Errorf("Expected an empty ledger, got %v", ledger.Answers)
+		}
+	})
+
+	t.Run("Should record answers", func(t *testing.T) {
+		readFile = os.ReadFile
+		path := filepath.Join(t.TempDir(), "ledger.json")
+
+		ledger, _ := loadLedger(path)
+		ledger.Record(key, "ABC", false)
+		ledger.Record(key, "ABC", false)
+		ledger.Record(key, "CMZ", true)
+		if err := saveLedger(path, ledger); err != nil {
+			t.Fatal(err)
+		}
+
+		saved, err := loadLedger(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry := saved.Entry(key)
+		if entry.Correct != "CMZ" {
+			t.Errorf("Expected correct answer CMZ, got %s", entry.Correct)
+		}
+		if len(entry.Wrong) != 1 || !entry.IsWrong("ABC") {
+			t.Errorf("Expected ABC to be recorded as wrong once, got %v", entry.Wrong)
+		}
+	})
+
+	t.Run("Should default to the user config directory", func(t *testing.T) {
+		configDir := mockUserConfigDir(t)
+
+		path, err := ledgerPath("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := filepath.Join(configDir, "aoc", "ledger.json"); path != expected {
+			t.Errorf("Expected %s, got %s", expected, path)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// logger traces what aoc does, it discards everything until setupLogger is called
+var logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// setupLogger logs to w at the level of the -v and -vv flags, in the -log-format
+func setupLogger(w io.Writer) error {
+	level := slog.LevelWarn
+	if verboseFlag {
+		level = slog.LevelInfo
+	}
+	if debugFlag {
+		level = slog.LevelDebug
+	}
+	options := &slog.HandlerOptions{Level: level}
+
+	switch logFormatFlag {
+	case "text":
+		logger = slog.New(slog.NewTextHandler(w, options))
+	case "json":
+		logger = slog.New(slog.NewJSONHandler(w, options))
+	default:
+		return usageError(fmt.Sprintf("%s is not a log format, use text or json", logFormatFlag))
+	}
+	return nil
+}
+
+// transport is the client requests are sent with
+func transport() httpClient {
+	if isOffline() {
+		return loggingClient{next: offlineClient{}}
+	}
+	if recordFlag != "" {
+		return loggingClient{next: recordingClient{dir: recordFlag, next: client}}
+	}
+	return loggingClient{next: client}
+}
+
+// loggingClient traces every request and response of the client it wraps
+type loggingClient struct {
+	next httpClient
+}
+
+func (c loggingClient) Do(req *http.Request) (*http.Response, error) {
+	target := redactURL(req.URL)
+	logger.Debug("request", "method", req.Method, "url", target, "headers", redactHeader(req.Header))
+
+	start := time.Now()
+	res, err := c.next.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		logger.Info("request failed", "method", req.Method, "url", target, "duration", duration, "error", err)
+		return res, err
+	}
+
+	logger.Info("response", "method", req.Method, "url", target, "status", res.StatusCode, "duration", duration)
+	logger.Debug("response headers", "url", target, "headers", redactHeader(res.Header))
+	return res, nil
+}
+
+// redactURL hides the parts of a url that may be secret, the path of anything but advent of code
+// is hidden as webhook urls carry their token in it
+func redactURL(u *url.URL) string {
+	safe := url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}
+	if aocURL, err := url.Parse(baseURL()); err != nil || u.Host != aocURL.Host {
+		safe.Path = "/" + redacted
+	}
+	return safe.String()
+}
+
+// redactHeader copies the header without the session cookie
+func redactHeader(header http.Header) http.Header {
+	safe := header.Clone()
+	for _, name := range []string{"Cookie", "Set-Cookie", "Authorization"} {
+		if safe.Get(name) != "" {
+			safe.Set(name, redacted)
+		}
+	}
+	return safe
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// mockLogger logs into the returned buffer for the test, at debug level when debug is set
+func mockLogger(t *testing.T, format string, debug bool) *bytes.Buffer {
+	var output bytes.Buffer
+	verboseFlag, debugFlag, logFormatFlag = false, debug, format
+	if err := setupLogger(&output); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logger = slog.New(slog.NewTextHandler(io.Discard, nil)) })
+	return &output
+}
+
+func TestLoggingRequests(t *testing.T) {
+	cookie := http.Cookie{Name: "session", Value: "abc123"}
+
+	t.Run("Should log requests without the session", func(t *testing.T) {
+		output := mockLogger(t, "text", true)
+		header := http.Header{}
+		header.Set("Set-Cookie", "session=def456")
+		client = &mockClient{res: http.Response{StatusCode: 200, Header: header, Body: mockBody("1\n")}}
+
+		res, err := get("https://adventofcode.com/2022/day/1/input", cookie)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		logs := output.String()
+		for _, expected := range []string{"method=GET", "url=https://adventofcode.com/2022/day/1/input", "status=200", "duration=", redacted} {
+			if !strings.Contains(logs, expected) {
+				t.Errorf("Expected %s in %q", expected, logs)
+			}
+		}
+		if strings.Contains(logs, "abc123") || strings.Contains(logs, "def456") {
+			t.Errorf("Expected the session to be redacted, got %q", logs)
+		}
+	})
+
+	t.Run("Should log failed requests as JSON", func(t *testing.T) {
+		output := mockLogger(t, "json", true)
+		client = &mockClient{err: errors.New("connection refused")}
+
+		if _, err := get("https://adventofcode.com/2022/day/1/input", cookie); err == nil {
+			t.Fatal("Expected an error")
+		}
+
+		if logs := output.String(); !strings.Contains(logs, `"msg":"request failed"`) || !strings.Contains(logs, "connection refused") {
+			t.Errorf("Unexpected logs %q", logs)
+		}
+	})
+
+	t.Run("Should only log warnings by default", func(t *testing.T) {
+		output := mockLogger(t, "text", false)
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody("1\n")}}
+
+		if _, err := get("https://adventofcode.com/2022/day/1/input", cookie); err != nil {
+			t.Fatal(err)
+		}
+		if output.Len() != 0 {
+			t.Errorf("Expected no logs, got %q", output.String())
+		}
+	})
+
+	t.Run("Should return error for unknown formats", func(t *testing.T) {
+		logFormatFlag = "xml"
+		if err := setupLogger(io.Discard); errorCode(err) != codeUsage {
+			t.Errorf("Expected usage error, got %v", err)
+		}
+	})
+}
+
+func TestRedactingURL(t *testing.T) {
+	tests := map[string]string{
+		"https://adventofcode.com/2022/leaderboard/private/view/1.json?x=1": "https://adventofcode.com/2022/leaderboard/private/view/1.json",
+		"https://hooks.slack.com/services/T000/B000/XXXX":                   "https://hooks.slack.com/" + redacted,
+	}
+
+	for input, expected := range tests {
+		req, _ := http.NewRequest("GET", input, nil)
+		if actual := redactURL(req.URL); actual != expected {
+			t.Errorf("Expected %s, got %s", expected, actual)
+		}
+	}
+}
@@ -2,10 +2,14 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 var (
@@ -13,56 +17,563 @@ var (
 )
 
 func main() {
-	url, sessionFlag, err := run()
+	os.Exit(execute(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// fetchCommand fetches a puzzle's input, unless it was already fetched and has not changed
+func fetchCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	url, err := parseFetchArgs(args)
 	if err != nil {
-		handleError(err, 2)
+		return err
+	}
+
+	output := outputFlag
+	if output == "-" && jsonFlag {
+		return usageError("Cannot write the input to stdout with -json")
 	}
 
-	sessionID, err := grabSessionID(sessionFlag)
+	now, err := eventNow()
 	if err != nil {
-		handleError(err, 2)
+		return err
+	}
+	year, day, err := parsePuzzleURL(url, now)
+	if err != nil {
+		return err
 	}
+	result.Year, result.Day = year, day
 
-	cookie, err := makeCookie(sessionID)
+	cookie, err := sessionCookie()
 	if err != nil {
-		handleError(err, 1)
+		return err
+	}
+
+	manifest, err := loadManifest(lockFlag)
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	if output != "-" {
+		result.Path = output
+		if content, err = lockedInput(manifest, output, year, day, cookie.Value); err != nil {
+			return err
+		}
 	}
 
+	if content != nil {
+		fmt.Fprintf(stderr, "%s was already fetched and has not changed\n", output)
+	} else {
+		if content, err = fetchInput(url, cookie); err != nil {
+			return err
+		}
+
+		if output != "-" || execFlag == "" {
+			if err := writeOutput(stdout, content, output); err != nil {
+				return err
+			}
+		}
+
+		if output != "-" {
+			if err := recordInput(output, year, day, cookie, content); err != nil {
+				return err
+			}
+		}
+	}
+	result.Bytes = len(content)
+
+	if execFlag != "" {
+		exitCode, err := runCommand(execFlag, output, content, stdout)
+		if err != nil {
+			return err
+		}
+		if exitCode != 0 {
+			return exitError{command: execFlag, code: exitCode}
+		}
+	}
+	return nil
+}
+
+// fetchInput fetches the input for a puzzle url and checks that it looks like an input
+func fetchInput(url string, cookie http.Cookie) (content []byte, err error) {
 	res, err := fetch(url, cookie)
 	if err != nil {
-		handleError(err, 18)
+		return content, err
 	}
 	defer res.Body.Close()
 
-	file, err := createOutputFile("inputs.txt")
+	content, err = readInput(res.Body)
 	if err != nil {
-		handleError(err, 18)
+		return content, err
 	}
 
-	if err := handleOutput(res.Body, file); err != nil {
-		handleError(err, 18)
+	if res.StatusCode != http.StatusOK {
+		return content, statusError("fetch the input of "+url, res, content)
 	}
+
+	return checkInput(content, allowSuspiciousFlag)
 }
 
-func handleError(err error, exitCode int) {
-	fmt.Fprintln(os.Stderr, err)
-	os.Exit(exitCode)
+// recordInput adds a fetched input to the -lock manifest
+func recordInput(path string, year, day int, cookie http.Cookie, content []byte) error {
+	manifest, err := loadManifest(lockFlag)
+	if err != nil {
+		return err
+	}
+
+	manifest.Record(newManifestEntry(path, year, day, cookie.Value, content, wallClock.Now()))
+	return saveManifest(lockFlag, manifest)
 }
 
-func handleOutput(body io.Reader, target *os.File) (err error) {
+// lockCommand checks every input in the -lock manifest against the files on disk
+func lockCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	if len(args) < 1 || args[0] != "verify" {
+		return usageError("Did you want to call \"lock verify\"?")
+	}
+
+	manifest, err := loadManifest(lockFlag)
+	if err != nil {
+		return err
+	}
+	if len(manifest.Inputs) == 0 {
+		return fmt.Errorf("No inputs recorded in %s", lockFlag)
+	}
+	result.Path = lockFlag
+	result.Data = manifest.Inputs
+
+	failures, err := verifyManifest(stdout, manifest)
+	if err != nil {
+		return err
+	}
+	if failures > 0 {
+		return withCode(codeCheckFailed, fmt.Errorf("%d inputs do not match %s", failures, lockFlag))
+	}
+	return nil
+}
+
+func leaderboardCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	id, action, err := parseLeaderboardArgs(args)
+	if err != nil {
+		return err
+	}
+
+	now, err := eventNow()
+	if err != nil {
+		return err
+	}
+
+	year, err := eventYear(now)
+	if err != nil {
+		return err
+	}
+	result.Year = year
+
+	if action == "history" {
+		return historyCommand(stdout, result, year, id, now.Location())
+	}
+
+	cookie, err := sessionCookie()
+	if err != nil {
+		return err
+	}
+
+	if action == "watch" {
+		sinks, err := watchSinks(stdout)
+		if err != nil {
+			return err
+		}
+		w := watcher{year: year, id: id, cookie: cookie, sinks: sinks}
+		return w.watch(stdout, stderr, intervalFlag)
+	}
+
+	body, age, err := cachedFetchLeaderboard(year, id, cookie, wallClock.Now())
+	if err != nil {
+		return err
+	}
+	if age > 0 {
+		fmt.Fprintf(stderr, "Using leaderboard cached %s ago, it can be refreshed in %s\n",
+			age.Round(time.Second), (leaderboardPollInterval - age).Round(time.Second))
+	}
+
+	leaderboard, err := parseLeaderboard(body)
+	if err != nil {
+		return err
+	}
+
+	if action == "export" {
+		if formatFlag == "sqlite" {
+			result.Path = dbFlag
+		}
+		return exportLeaderboard(stdout, leaderboard, id, formatFlag, dbFlag, wallClock.Now().In(now.Location()).Add(-age))
+	}
+
+	if daysFlag {
+		stats, err := dayStats(leaderboard, now.Location())
+		if err != nil {
+			return err
+		}
+		result.Data = stats
+		return renderDayStats(stdout, stats)
+	}
+
+	standings, err := scoreMembers(leaderboard, scoreFlag, now.Location())
+	if err != nil {
+		return err
+	}
+	result.Data = standings
+	return renderStandings(stdout, standings)
+}
+
+// historyCommand shows the saved snapshots of a leaderboard without touching the network
+func historyCommand(w io.Writer, result *Result, year int, id string, est *time.Location) error {
+	snapshots, err := loadSnapshots(year, id)
+	if err != nil {
+		return err
+	}
+
+	if dayFlag == 0 {
+		result.Data = snapshots
+		return renderRankHistory(w, snapshots)
+	}
+
+	if dayFlag < 1 || dayFlag > lastDay {
+		return fmt.Errorf("%d is not a valid day", dayFlag)
+	}
+
+	stars, err := replayDay(snapshots, dayFlag, est)
+	if err != nil {
+		return err
+	}
+	result.Data = stars
+	return renderReplay(w, stars)
+}
+
+// statsCommand shows the account's stars, times and ranks for the -year event, or stars for every event
+func statsCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	now, err := eventNow()
+	if err != nil {
+		return err
+	}
+
+	cookie, err := sessionCookie()
+	if err != nil {
+		return err
+	}
+
+	if yearFlag != 0 {
+		year, err := eventYear(now)
+		if err != nil {
+			return err
+		}
+
+		stats, err := fetchYearStats(year, cookie)
+		if err != nil {
+			return err
+		}
+		result.Year, result.Data = year, stats
+		return renderYearStats(stdout, stats)
+	}
+
+	// the summary only needs the stars, so it leaves the personal leaderboards alone
+	var allStats []YearStats
+	for year := firstYear; year <= latestEvent(now); year++ {
+		stats, err := fetchYearStars(year, cookie, wallClock.Now())
+		if err != nil {
+			return err
+		}
+		allStats = append(allStats, stats)
+	}
+	result.Data = allStats
+	return renderAllYears(stdout, allStats)
+}
+
+// newCommand fetches a day's input if it is not already there and scaffolds the day's solution from templates
+func newCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	now, err := eventNow()
+	if err != nil {
+		return err
+	}
+
+	year, day, err := parsePuzzleArgs(args, now)
+	if err != nil {
+		return err
+	}
+	result.Year, result.Day = year, day
+
+	cookie, err := sessionCookie()
+	if err != nil {
+		return err
+	}
+
+	dir := dayDir(year, day)
+	result.Path = dir
+	var created []string
+	inputPath := filepath.Join(dir, inputFileName)
+	exists, err := checkFileExist(inputPath)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		content, err := fetchInput(puzzleURL(year, day), cookie)
+		if err != nil {
+			return err
+		}
+		if _, err := writeIfMissing(inputPath, content); err != nil {
+			return err
+		}
+		if err := recordInput(inputPath, year, day, cookie, content); err != nil {
+			return err
+		}
+		created = append(created, inputPath)
+		fmt.Fprintln(stdout, "Created", inputPath)
+	}
+
+	puzzle, err := fetchPuzzle(year, day, cookie)
+	if err != nil {
+		return err
+	}
+
+	if puzzle.Example != "" {
+		examplePath := filepath.Join(dir, exampleFileName)
+		written, err := writeIfMissing(examplePath, []byte(puzzle.Example))
+		if err != nil {
+			return err
+		}
+		if written {
+			created = append(created, examplePath)
+			fmt.Fprintln(stdout, "Created", examplePath)
+		}
+	}
+
+	pack, err := findPack(templatesFlag, langFlag)
+	if err != nil {
+		return err
+	}
+
+	data := scaffoldData{Puzzle: puzzle, InputFile: inputFileName, ExampleFile: exampleFileName, Run: pack.Run, Test: pack.Test}
+	rendered, err := renderTemplates(pack, dir, data)
+	for _, name := range rendered {
+		fmt.Fprintln(stdout, "Created", name)
+	}
+	created = append(created, rendered...)
+	result.Data = created
+	if err != nil || len(rendered) == 0 {
+		return err
+	}
+
+	for _, command := range pack.PostCreate {
+		exitCode, err := runCommandIn(dir, command, inputFileName, nil, stdout)
+		if err != nil {
+			return err
+		}
+		if exitCode != 0 {
+			return exitError{command: "post_create command " + command, code: exitCode}
+		}
+	}
+	return nil
+}
+
+// solveCommand runs a day's solution and checks its answer against the ledger, submitting it when asked
+func solveCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	now, err := eventNow()
+	if err != nil {
+		return err
+	}
+
+	year, day, err := parsePuzzleArgs(args, now)
+	if err != nil {
+		return err
+	}
+
+	result.Year, result.Day = year, day
+
+	if partFlag != 1 && partFlag != 2 {
+		return usageError(fmt.Sprintf("%d is not a valid part", partFlag))
+	}
+	key := PartKey{Year: year, Day: day, Part: partFlag}
+
+	pack, err := findPack(templatesFlag, langFlag)
+	if err != nil {
+		return err
+	}
+
+	output, err := runSolution(pack, dayDir(year, day))
+	fmt.Fprint(stdout, output)
+	if err != nil {
+		return err
+	}
+
+	answer, err := extractAnswer(output, answerPatternFlag, key.Part)
+	if err != nil {
+		return err
+	}
+	result.Data = Answer{Part: key.Part, Answer: answer}
+
+	path, err := ledgerPath(ledgerFlag)
+	if err != nil {
+		return err
+	}
+
+	ledger, err := loadLedger(path)
+	if err != nil {
+		return err
+	}
+
+	entry := ledger.Entry(key)
+	switch {
+	case entry.Correct == answer:
+		fmt.Fprintf(stdout, "%s is the right answer for day %d part %d\n", answer, day, key.Part)
+		return nil
+	case entry.Correct != "":
+		return withCode(codeWrongAnswer, fmt.Errorf("%s is not the right answer for day %d part %d, it was %s", answer, day, key.Part, entry.Correct))
+	case entry.IsWrong(answer):
+		return withCode(codeWrongAnswer, fmt.Errorf("%s was already rejected for day %d part %d", answer, day, key.Part))
+	case !submitFlag:
+		fmt.Fprintf(stdout, "The answer for day %d part %d is not known yet, use -submit to submit %s\n", day, key.Part, answer)
+		return nil
+	}
+
+	cookie, err := sessionCookie()
+	if err != nil {
+		return err
+	}
+
+	verdict, message, err := submitAnswer(key, answer, cookie)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, message)
+
+	switch verdict {
+	case correctVerdict, wrongVerdict:
+		ledger.Record(key, answer, verdict == correctVerdict)
+		if err := saveLedger(path, ledger); err != nil {
+			return err
+		}
+		if verdict == wrongVerdict {
+			return withCode(codeWrongAnswer, fmt.Errorf("%s is not the right answer for day %d part %d", answer, day, key.Part))
+		}
+		return nil
+	case tooRecentVerdict:
+		return withCode(codeRateLimited, errors.New("Answer was submitted too recently, wait before submitting again"))
+	case wrongLevelVerdict:
+		return withCode(codeLocked, fmt.Errorf("Day %d part %d is already solved or not unlocked yet", day, key.Part))
+	}
+	return errors.New("Could not understand the response to the answer")
+}
+
+// benchCommand benchmarks every solution of the -year event and compares it to the baseline
+func benchCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	now, err := eventNow()
+	if err != nil {
+		return err
+	}
+
+	year, err := eventYear(now)
+	if err != nil {
+		return err
+	}
+	result.Year = year
+
+	pack, err := findPack(templatesFlag, langFlag)
+	if err != nil {
+		return err
+	}
+
+	report, err := benchYear(pack, year, runsFlag)
+	if err != nil {
+		return err
+	}
+
+	path := baselinePath(baselineFlag, year)
+	baseline, err := loadBaseline(path)
+	if err != nil {
+		return err
+	}
+	compareBaseline(&report, baseline)
+	result.Data = report
+
+	if err := renderBench(stdout, report); err != nil {
+		return err
+	}
+
+	if saveBaselineFlag {
+		if err := saveBaseline(path, report); err != nil {
+			return err
+		}
+		result.Path = path
+	}
+
+	if regressions := report.Regressions(); len(regressions) > 0 {
+		return withCode(codeCheckFailed, fmt.Errorf("Days %v are slower than the baseline", regressions))
+	}
+	return nil
+}
+
+// verifyCommand checks that every solution still gets its accepted answers, for the -year event if it is set
+func verifyCommand(args []string, stdout, stderr io.Writer, result *Result) error {
+	path, err := ledgerPath(ledgerFlag)
+	if err != nil {
+		return err
+	}
+
+	ledger, err := loadLedger(path)
+	if err != nil {
+		return err
+	}
+
+	pack, err := findPack(templatesFlag, langFlag)
+	if err != nil {
+		return err
+	}
+
+	results, err := verifyAnswers(pack, ledger, answerPatternFlag, yearFlag)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return errors.New("No accepted answers in the ledger to verify")
+	}
+	result.Data = results
+
+	failures, err := renderVerify(stdout, results)
+	if err != nil {
+		return err
+	}
+	if failures > 0 {
+		return withCode(codeCheckFailed, fmt.Errorf("%d answers no longer match", failures))
+	}
+	return nil
+}
+
+func handleOutput(body io.Reader, target io.Writer) (err error) {
 	if _, err := io.Copy(target, body); err != nil {
 		return err
 	}
 	return err
 }
 
+// writeOutput saves the input to the named file, or writes it to stdout when the name is "-"
+func writeOutput(stdout io.Writer, content []byte, name string) error {
+	if name == "-" {
+		return handleOutput(bytes.NewReader(content), stdout)
+	}
+
+	file, err := createOutputFile(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return handleOutput(bytes.NewReader(content), file)
+}
+
 func createOutputFile(name string) (file *os.File, err error) {
 	exists, err := checkFileExist(name)
 	if err != nil {
 		return file, err
 	}
 	if exists {
-		return file, errors.New("inputs.txt already exists in the directory")
+		return file, withCode(codeExists, fmt.Errorf("%s already exists", name))
 	}
 	return createFile(name)
 }
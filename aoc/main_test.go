@@ -2,9 +2,15 @@ package main
 
 import (
 	"bytes"
+	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/streakinthesky/adventofcode-fetcher/aoc/aoctest"
 )
 
 func TestOutputsToFile(t *testing.T) {
@@ -69,7 +75,7 @@ func TestErrorCreatingFileIfExists(t *testing.T) {
 
 	createFile = mockCreateFile
 
-	expectedError := "inputs.txt already exists in the directory"
+	expectedError := fmt.Sprintf("%s already exists", testFile.Name())
 
 	createdFile, err := createOutputFile(testFile.Name())
 	if err == nil {
@@ -92,3 +98,128 @@ func mockCreateFile(name string) (file *os.File, err error) {
 	}
 	return file, nil
 }
+
+func TestAgainstFakeServer(t *testing.T) {
+	chdir(t)
+	mockCacheDir(t)
+	client = &http.Client{}
+	createFile = os.Create
+	t.Cleanup(func() { baseURLFlag = "" })
+
+	server := aoctest.NewServer(aoctest.NewClock(time.Date(2022, time.December, 5, 0, 0, 0, 0, aoctest.EventLocation)))
+	t.Cleanup(server.Close)
+	server.SetInput(2022, 1, "1000\n2000\n")
+	server.SetLeaderboard(2022, "1", mockLeaderboardJSON)
+	flags := []string{"-base-url", server.URL, "-session", aoctest.DefaultSession}
+
+	t.Run("Should fetch an input once", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			var stdout, stderr bytes.Buffer
+			if code := execute(append([]string{"fetch", "2022/1", "-o", "input.txt"}, flags...), &stdout, &stderr); code != 0 {
+				t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+			}
+		}
+
+		content, err := os.ReadFile("input.txt")
+		if err != nil || string(content) != "1000\n2000\n" {
+			t.Errorf("Expected the input to be saved, got %q, %v", content, err)
+		}
+		if requests := server.Requests(); len(requests) != 1 {
+			t.Errorf("Expected a single request, got %v", requests)
+		}
+	})
+
+	t.Run("Should not overwrite the input of another day", func(t *testing.T) {
+		chdir(t)
+		server.SetInput(2022, 2, "3000\n")
+
+		var stdout, stderr bytes.Buffer
+		if code := execute(append([]string{"fetch", "2022/1"}, flags...), &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+		}
+		if code := execute(append([]string{"fetch", "2022/2"}, flags...), &stdout, &stderr); code != exitIO {
+			t.Errorf("Expected exit code %d, got %d: %s", exitIO, code, stderr.String())
+		}
+
+		content, err := os.ReadFile(outputFlag)
+		if err != nil || string(content) != "1000\n2000\n" {
+			t.Errorf("Expected the input of day 1 to be kept, got %q, %v", content, err)
+		}
+	})
+
+	t.Run("Should not reuse the input of another session", func(t *testing.T) {
+		chdir(t)
+
+		var stdout, stderr bytes.Buffer
+		if code := execute(append([]string{"fetch", "2022/1"}, flags...), &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+		}
+		requests := len(server.Requests())
+		if code := execute([]string{"fetch", "2022/1", "-base-url", server.URL, "-session", "other"}, &stdout, &stderr); code != exitIO {
+			t.Errorf("Expected exit code %d, got %d: %s", exitIO, code, stderr.String())
+		}
+		if len(server.Requests()) != requests {
+			t.Errorf("Expected no request for the other session, got %v", server.Requests()[requests:])
+		}
+	})
+
+	t.Run("Should not fetch a locked day", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute(append([]string{"fetch", "2022/6", "-o", "day6.txt"}, flags...), &stdout, &stderr); code != exitLocked {
+			t.Errorf("Expected exit code %d, got %d: %s", exitLocked, code, stderr.String())
+		}
+	})
+
+	t.Run("Should exit with the command exit code when post_create fails", func(t *testing.T) {
+		dir := chdir(t)
+		packDir := filepath.Join(dir, "packs", "failing")
+		os.MkdirAll(packDir, 0o755)
+		os.WriteFile(filepath.Join(packDir, "pack.json"), []byte(`{"run": "true", "post_create": ["sh setup.sh"]}`), 0o644)
+		os.WriteFile(filepath.Join(packDir, "setup.sh.tmpl"), []byte("exit 3\n"), 0o644)
+
+		var stdout, stderr bytes.Buffer
+		args := append([]string{"new", "2022/1", "-templates", filepath.Join(dir, "packs"), "-lang", "failing"}, flags...)
+		if code := execute(args, &stdout, &stderr); code != exitCommand {
+			t.Errorf("Expected exit code %d, got %d: %s", exitCommand, code, stderr.String())
+		}
+	})
+
+	t.Run("Should show a leaderboard", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := execute(append([]string{"leaderboard", "1", "-year", "2022"}, flags...), &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), "alice") {
+			t.Errorf("Expected the standings, got %q", stdout.String())
+		}
+	})
+}
+
+func TestFetchingInputStatus(t *testing.T) {
+	cookie := http.Cookie{Name: "session", Value: "abc123"}
+	url := "https://adventofcode.com/2021/day/1"
+	t.Cleanup(func() { allowSuspiciousFlag = false })
+
+	tests := []struct {
+		status   int
+		body     string
+		expected string
+	}{
+		{502, "upstream exploded", codeHTTPStatus},
+		{429, "slow down", codeRateLimited},
+		{401, "who are you", codeSession},
+		{404, "Please don't repeatedly request this endpoint before it unlocks! The calendar countdown is synchronized with the server time.", codeLocked},
+		{400, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", codeSession},
+	}
+
+	for _, allowSuspicious := range []bool{false, true} {
+		allowSuspiciousFlag = allowSuspicious
+		for _, test := range tests {
+			client = &mockClient{res: http.Response{StatusCode: test.status, Status: fmt.Sprint(test.status), Body: mockBody(test.body)}}
+
+			if _, err := fetchInput(url, cookie); errorCode(err) != test.expected {
+				t.Errorf("Expected %s for %d %q, got %v", test.expected, test.status, test.body, err)
+			}
+		}
+	}
+}
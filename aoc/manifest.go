@@ -0,0 +1,167 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+var errInputModified = errors.New("input has been edited or is corrupted since it was fetched")
+
+// Manifest records every fetched input so they can be checked for changes without the network
+type Manifest struct {
+	Inputs []ManifestEntry `json:"inputs"`
+}
+
+// ManifestEntry is a fetched input
+type ManifestEntry struct {
+	Path string `json:"path"`
+	Year int    `json:"year"`
+	Day  int    `json:"day"`
+	// Account identifies the session the input was fetched with, without storing the session
+	Account   string    `json:"account"`
+	SHA256    string    `json:"sha256"`
+	Size      int       `json:"size"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// accountID is a short fingerprint of a session, the same for everyone sharing an account
+func accountID(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func manifestPath(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+func newManifestEntry(path string, year, day int, sessionID string, content []byte, fetchedAt time.Time) ManifestEntry {
+	return ManifestEntry{
+		Path:      manifestPath(path),
+		Year:      year,
+		Day:       day,
+		Account:   accountID(sessionID),
+		SHA256:    checksum(content),
+		Size:      len(content),
+		FetchedAt: fetchedAt,
+	}
+}
+
+// loadManifest reads the manifest, which is empty if it has never been written
+func loadManifest(path string) (manifest Manifest, err error) {
+	content, err := readFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return manifest, fmt.Errorf("%s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+func saveManifest(path string, manifest Manifest) error {
+	sort.Slice(manifest.Inputs, func(i, j int) bool { return manifest.Inputs[i].Path < manifest.Inputs[j].Path })
+
+	content, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// Find returns the entry for an input's path
+func (m Manifest) Find(path string) (entry ManifestEntry, ok bool) {
+	path = manifestPath(path)
+	for _, entry := range m.Inputs {
+		if entry.Path == path {
+			return entry, true
+		}
+	}
+	return entry, false
+}
+
+// Record adds the entry, replacing any entry with the same path
+func (m *Manifest) Record(entry ManifestEntry) {
+	for i := range m.Inputs {
+		if m.Inputs[i].Path == entry.Path {
+			m.Inputs[i] = entry
+			return
+		}
+	}
+	m.Inputs = append(m.Inputs, entry)
+}
+
+// checkEntry makes sure the input on disk is the one that was fetched
+func checkEntry(entry ManifestEntry) (content []byte, err error) {
+	content, err = readFile(filepath.FromSlash(entry.Path))
+	if err != nil {
+		return content, err
+	}
+
+	if len(content) != entry.Size || checksum(content) != entry.SHA256 {
+		return content, fmt.Errorf("%s: %w", entry.Path, errInputModified)
+	}
+	return content, nil
+}
+
+// lockedInput returns the input of the day at path if it was already fetched with the session's account and has not changed since.
+// It returns nothing if there is no input at path yet, and an error if path has any other file.
+func lockedInput(manifest Manifest, path string, year, day int, sessionID string) (content []byte, err error) {
+	exists, err := checkFileExist(path)
+	if err != nil || !exists {
+		return content, err
+	}
+
+	entry, ok := manifest.Find(path)
+	if !ok {
+		return content, withCode(codeExists, fmt.Errorf("%s already exists", path))
+	}
+	if entry.Year != year || entry.Day != day {
+		return content, withCode(codeExists, fmt.Errorf("%s already exists with the input of %d day %d", path, entry.Year, entry.Day))
+	}
+	// inputs differ by account, another account's input is not this session's
+	if entry.Account != accountID(sessionID) {
+		return content, withCode(codeExists, fmt.Errorf("%s already exists with the input of account %s", path, entry.Account))
+	}
+	return checkEntry(entry)
+}
+
+// verifyManifest checks every input in the manifest and writes the result of each
+func verifyManifest(w io.Writer, manifest Manifest) (failures int, err error) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Path\tYear\tDay\tAccount\tStatus")
+
+	for _, entry := range manifest.Inputs {
+		status := "ok"
+		if _, err := checkEntry(entry); err != nil {
+			failures++
+			switch {
+			case errors.Is(err, errInputModified):
+				status = "modified"
+			case errors.Is(err, os.ErrNotExist):
+				status = "missing"
+			default:
+				status = err.Error()
+			}
+		}
+		fmt.Fprintf(table, "%s\t%d\t%d\t%s\t%s\n", entry.Path, entry.Year, entry.Day, entry.Account, status)
+	}
+	return failures, table.Flush()
+}
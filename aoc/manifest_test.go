@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccountID(t *testing.T) {
+	if accountID("abc") != accountID("abc") {
+		t.Error("Expected the same session to have the same account")
+	}
+	if accountID("abc") == accountID("abd") {
+		t.Error("Expected different sessions to have different accounts")
+	}
+	if id := accountID("abc"); len(id) != 12 || strings.Contains(id, "abc") {
+		t.Errorf("Expected a short fingerprint, got %s", id)
+	}
+}
+
+func TestRecordingManifest(t *testing.T) {
+	fetchedAt := time.Unix(1669870800, 0).UTC()
+	var manifest Manifest
+	manifest.Record(newManifestEntry("2022/day01/inputs.txt", 2022, 1, "abc", []byte("1\n"), fetchedAt))
+	manifest.Record(newManifestEntry("inputs.txt", 2022, 2, "abc", []byte("2\n"), fetchedAt))
+	manifest.Record(newManifestEntry("./inputs.txt", 2022, 3, "abc", []byte("3\n"), fetchedAt))
+
+	if len(manifest.Inputs) != 2 {
+		t.Fatalf("Expected the same path to be replaced, got %+v", manifest.Inputs)
+	}
+
+	entry, ok := manifest.Find("inputs.txt")
+	if !ok || entry.Day != 3 || entry.Size != 2 || entry.SHA256 != checksum([]byte("3\n")) {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if _, ok := manifest.Find("2022/day02/inputs.txt"); ok {
+		t.Error("Expected no entry")
+	}
+
+	t.Run("Should save and load manifest", func(t *testing.T) {
+		readFile = os.ReadFile
+		path := filepath.Join(t.TempDir(), "aoc.lock")
+
+		if empty, err := loadManifest(path); err != nil || len(empty.Inputs) != 0 {
+			t.Fatalf("Expected an empty manifest, got %+v, %v", empty, err)
+		}
+
+		if err := saveManifest(path, manifest); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := loadManifest(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded.Inputs) != 2 || loaded.Inputs[0].Path != "2022/day01/inputs.txt" || !loaded.Inputs[1].FetchedAt.Equal(fetchedAt) {
+			t.Errorf("Unexpected manifest %+v", loaded)
+		}
+	})
+}
+
+func TestCheckingInputs(t *testing.T) {
+	readFile = os.ReadFile
+	chdir(t)
+
+	os.WriteFile("inputs.txt", []byte("1\n"), 0o644)
+	os.WriteFile("edited.txt", []byte("2\n"), 0o644)
+	os.WriteFile("unrecorded.txt", []byte("3\n"), 0o644)
+
+	var manifest Manifest
+	manifest.Record(newManifestEntry("inputs.txt", 2022, 1, "abc", []byte("1\n"), time.Time{}))
+	manifest.Record(newManifestEntry("edited.txt", 2022, 2, "abc", []byte("two\n"), time.Time{}))
+	manifest.Record(newManifestEntry("missing.txt", 2022, 3, "abc", []byte("3\n"), time.Time{}))
+
+	t.Run("Should return unchanged input", func(t *testing.T) {
+		content, err := lockedInput(manifest, "inputs.txt", 2022, 1, "abc")
+		if err != nil || string(content) != "1\n" {
+			t.Errorf("Expected the input, got %q, %v", content, err)
+		}
+	})
+
+	t.Run("Should return nothing for missing input", func(t *testing.T) {
+		content, err := lockedInput(manifest, "missing.txt", 2022, 3, "abc")
+		if err != nil || content != nil {
+			t.Errorf("Expected nothing, got %q, %v", content, err)
+		}
+	})
+
+	t.Run("Should return error for modified input", func(t *testing.T) {
+		if _, err := lockedInput(manifest, "edited.txt", 2022, 2, "abc"); !errors.Is(err, errInputModified) {
+			t.Errorf("Expected modified input error, got %v", err)
+		}
+	})
+
+	t.Run("Should return error for unrecorded input", func(t *testing.T) {
+		if _, err := lockedInput(manifest, "unrecorded.txt", 2022, 4, "abc"); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Should return error for the input of another day", func(t *testing.T) {
+		if _, err := lockedInput(manifest, "inputs.txt", 2022, 2, "abc"); errorCode(err) != codeExists {
+			t.Errorf("Expected an exists error, got %v", err)
+		}
+	})
+
+	t.Run("Should return error for the input of another account", func(t *testing.T) {
+		if _, err := lockedInput(manifest, "inputs.txt", 2022, 1, "abd"); errorCode(err) != codeExists {
+			t.Errorf("Expected an exists error, got %v", err)
+		}
+	})
+
+	t.Run("Should report every input", func(t *testing.T) {
+		var output bytes.Buffer
+		failures, err := verifyManifest(&output, manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if failures != 2 {
+			t.Errorf("Expected 2 failures, got %d", failures)
+		}
+
+		for _, line := range []string{"inputs.txt", "edited.txt", "missing.txt"} {
+			if !strings.Contains(output.String(), line) {
+				t.Errorf("Expected %s in %q", line, output.String())
+			}
+		}
+		if !strings.Contains(output.String(), "modified") || !strings.Contains(output.String(), "missing") {
+			t.Errorf("Unexpected output %q", output.String())
+		}
+	})
+}
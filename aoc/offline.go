@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const offlineEnv = "AOC_OFFLINE"
+
+// isOffline returns whether aoc must not touch the network, from -offline or AOC_OFFLINE
+func isOffline() bool {
+	switch getEnv(offlineEnv) {
+	case "1", "true":
+		return true
+	}
+	return offlineFlag
+}
+
+// offlineClient refuses every request, so nothing can reach the network while offline
+type offlineClient struct{}
+
+func (offlineClient) Do(req *http.Request) (*http.Response, error) {
+	return nil, withCode(codeOffline, fmt.Errorf("%s is not available offline", redactURL(req.URL)))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+func mockOffline(t *testing.T) {
+	offlineFlag = true
+	t.Cleanup(func() { offlineFlag = false })
+}
+
+func TestOfflineTransport(t *testing.T) {
+	client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody("")}}
+	req, _ := http.NewRequest("GET", baseURL()+"/2022/day/1/input", nil)
+
+	t.Run("Should refuse requests with AOC_OFFLINE", func(t *testing.T) {
+		getEnv = func(key string) string {
+			if key == offlineEnv {
+				return "1"
+			}
+			return ""
+		}
+		t.Cleanup(func() { getEnv = os.Getenv })
+
+		_, err := transport().Do(req)
+		if errorCode(err) != codeOffline {
+			t.Errorf("Expected an offline error, got %v", err)
+		}
+		if exitCode(err) != exitOffline {
+			t.Errorf("Expected exit code %d, got %d", exitOffline, exitCode(err))
+		}
+	})
+
+	t.Run("Should refuse requests with -offline", func(t *testing.T) {
+		mockOffline(t)
+		if _, err := transport().Do(req); errorCode(err) != codeOffline {
+			t.Errorf("Expected an offline error, got %v", err)
+		}
+	})
+
+	t.Run("Should not be a network error", func(t *testing.T) {
+		mockOffline(t)
+		cookie := http.Cookie{Name: "session", Value: "abc123"}
+		if _, err := get(baseURL()+"/2022/day/1/input", cookie); exitCode(err) != exitOffline {
+			t.Errorf("Expected exit code %d, got %d", exitOffline, exitCode(err))
+		}
+	})
+
+	t.Run("Should send requests when online", func(t *testing.T) {
+		if _, err := transport().Do(req); err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+	})
+}
+
+func TestOfflineLeaderboard(t *testing.T) {
+	cookie := http.Cookie{Name: "session", Value: "abc123"}
+	now := getNow(t, INSIDE_ADVENT_DATE)
+
+	t.Run("Should use an expired cache", func(t *testing.T) {
+		mockCacheDir(t)
+		client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+		if _, _, err := cachedFetchLeaderboard(2022, "1", cookie, now); err != nil {
+			t.Fatal(err)
+		}
+
+		mockOffline(t)
+		_, age, err := cachedFetchLeaderboard(2022, "1", cookie, now.Add(24*time.Hour))
+		if err != nil {
+			t.Fatalf("Should not have error, got error: %s", err.Error())
+		}
+		if age != 24*time.Hour {
+			t.Errorf("Expected age of 24h, got %s", age)
+		}
+	})
+
+	t.Run("Should return error without a cache", func(t *testing.T) {
+		mockCacheDir(t)
+		mockOffline(t)
+		if _, _, err := cachedFetchLeaderboard(2022, "1", cookie, now); errorCode(err) != codeOffline {
+			t.Errorf("Expected an offline error, got %v", err)
+		}
+	})
+}
+
+func TestOfflinePuzzle(t *testing.T) {
+	cookie := http.Cookie{Name: "session", Value: "abc123"}
+
+	t.Run("Should read the saved page", func(t *testing.T) {
+		mockCacheDir(t)
+		client = &mockPageClient{pages: map[string]string{"/2022/day/5": mockPuzzlePage}}
+		if _, err := fetchPuzzle(2022, 5, cookie); err != nil {
+			t.Fatal(err)
+		}
+
+		mockOffline(t)
+		puzzle, err := fetchPuzzle(2022, 5, cookie)
+		if err != nil {
+			t.Fatalf("Should not have error, got error: %s", err.Error())
+		}
+		if puzzle.ExampleAnswer != "CMZ" {
+			t.Errorf("Expected example answer CMZ, got %s", puzzle.ExampleAnswer)
+		}
+	})
+
+	t.Run("Should return error without a saved page", func(t *testing.T) {
+		mockCacheDir(t)
+		mockOffline(t)
+		_, err := fetchPuzzle(2022, 5, cookie)
+		if errorCode(err) != codeOffline || errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected an offline error, got %v", err)
+		}
+	})
+}
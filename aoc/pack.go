@@ -0,0 +1,172 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const packManifest = "pack.json"
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// Pack is a named set of templates for a language, with the commands to set up, run and test a day
+type Pack struct {
+	Name string `json:"name"`
+	// Files are the templates to render, every .tmpl file when empty
+	Files []string `json:"files"`
+	// PostCreate are commands to run in the day's directory after it is scaffolded
+	PostCreate []string `json:"post_create"`
+	// Run runs a day's solution, {input} is replaced with the input's path
+	Run string `json:"run"`
+	// Test runs a day's tests
+	Test string `json:"test"`
+	// Build builds a day's solution once before it is benchmarked
+	Build string `json:"build"`
+	// Bench runs a built solution when benchmarking, so the build tool is not timed. Run is used when empty.
+	Bench string `json:"bench"`
+
+	templates fs.FS
+	// topLevel packs only render the templates directly in their directory, like the custom pack whose subdirectories are other packs
+	topLevel bool
+}
+
+func (p Pack) renders(name string) bool {
+	if len(p.Files) == 0 {
+		return strings.HasSuffix(name, templateExt)
+	}
+	for _, file := range p.Files {
+		if file == name {
+			return true
+		}
+	}
+	return false
+}
+
+func readPack(templates fs.FS, name string) (pack Pack, err error) {
+	manifest, err := fs.ReadFile(templates, packManifest)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return pack, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(manifest, &pack); err != nil {
+			return pack, fmt.Errorf("%s %s: %w", name, packManifest, err)
+		}
+	}
+
+	if pack.Name == "" {
+		pack.Name = name
+	}
+	pack.templates = templates
+	return pack, nil
+}
+
+func hasTemplates(templates fs.FS) bool {
+	entries, err := fs.ReadDir(templates, ".")
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && (entry.Name() == packManifest || strings.HasSuffix(entry.Name(), templateExt)) {
+			return true
+		}
+	}
+	return false
+}
+
+// templatesDir returns dir, or the templates directory in the user's config directory when dir is empty
+func templatesDir(dir string) (string, error) {
+	if dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			return dir, err
+		}
+		return dir, nil
+	}
+
+	configDir, err := userConfigDir()
+	if err != nil {
+		return "", nil
+	}
+	return filepath.Join(configDir, "aoc", "templates"), nil
+}
+
+// discoverPacks returns the built in packs, overridden by the packs in the templates directory.
+// Each directory with templates is a pack, templates directly in the templates directory are the "custom" pack.
+func discoverPacks(dir string) (packs map[string]Pack, err error) {
+	packs = map[string]Pack{}
+
+	builtin, err := fs.ReadDir(builtinTemplates, "templates")
+	if err != nil {
+		return packs, err
+	}
+	for _, entry := range builtin {
+		templates, err := fs.Sub(builtinTemplates, "templates/"+entry.Name())
+		if err != nil {
+			return packs, err
+		}
+		pack, err := readPack(templates, entry.Name())
+		if err != nil {
+			return packs, err
+		}
+		packs[pack.Name] = pack
+	}
+
+	dir, err = templatesDir(dir)
+	if err != nil || dir == "" {
+		return packs, err
+	}
+
+	root := os.DirFS(dir)
+	if hasTemplates(root) {
+		pack, err := readPack(root, "custom")
+		if err != nil {
+			return packs, err
+		}
+		pack.topLevel = true
+		packs[pack.Name] = pack
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return packs, err
+	}
+	for _, entry := range entries {
+		templates := os.DirFS(filepath.Join(dir, entry.Name()))
+		if !entry.IsDir() || !hasTemplates(templates) {
+			continue
+		}
+		pack, err := readPack(templates, entry.Name())
+		if err != nil {
+			return packs, err
+		}
+		packs[pack.Name] = pack
+	}
+
+	return packs, nil
+}
+
+// findPack returns the named pack from the templates directory or the built in packs
+func findPack(dir, name string) (pack Pack, err error) {
+	packs, err := discoverPacks(dir)
+	if err != nil {
+		return pack, err
+	}
+
+	pack, ok := packs[name]
+	if !ok {
+		names := make([]string, 0, len(packs))
+		for packName := range packs {
+			names = append(names, packName)
+		}
+		sort.Strings(names)
+		return pack, fmt.Errorf("No template pack named %s, use one of: %s", name, strings.Join(names, ", "))
+	}
+	return pack, nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoveringPacks(t *testing.T) {
+	t.Run("Should include the built in packs", func(t *testing.T) {
+		mockUserConfigDir(t)
+
+		packs, err := discoverPacks("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"go", "python", "rust"} {
+			if _, ok := packs[name]; !ok {
+				t.Errorf("Expected built in pack %s", name)
+			}
+		}
+		if packs["python"].Run != "python3 solution.py {input}" {
+			t.Errorf("Expected the python pack's run command, got %s", packs["python"].Run)
+		}
+	})
+
+	t.Run("Should discover packs in the user's templates directory", func(t *testing.T) {
+		configDir := mockUserConfigDir(t)
+		packDir := filepath.Join(configDir, "aoc", "templates", "python")
+		os.MkdirAll(packDir, 0o755)
+		os.WriteFile(filepath.Join(packDir, "pack.json"), []byte(`{"run": "pypy3 solution.py {input}", "post_create": ["touch .created"]}`), 0o644)
+		os.WriteFile(filepath.Join(packDir, "solution.py.tmpl"), []byte("# {{.Title}}\n"), 0o644)
+
+		pack, err := findPack("", "python")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pack.Run != "pypy3 solution.py {input}" || len(pack.PostCreate) != 1 {
+			t.Errorf("Expected the user's python pack to replace the built in one, got %+v", pack)
+		}
+	})
+
+	t.Run("Should only render the pack's files", func(t *testing.T) {
+		dir := t.TempDir()
+		packDir := filepath.Join(dir, "kotlin")
+		os.MkdirAll(packDir, 0o755)
+		os.WriteFile(filepath.Join(packDir, "pack.json"), []byte(`{"files": ["Main.kt.tmpl"]}`), 0o644)
+		os.WriteFile(filepath.Join(packDir, "Main.kt.tmpl"), []byte("// {{.Title}}\n"), 0o644)
+		os.WriteFile(filepath.Join(packDir, "notes.md.tmpl"), []byte("notes\n"), 0o644)
+
+		pack, err := findPack(dir, "kotlin")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		created, err := renderTemplates(pack, t.TempDir(), mockScaffoldData())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(created) != 1 || filepath.Base(created[0]) != "Main.kt" {
+			t.Errorf("Expected only Main.kt to be created, got %v", created)
+		}
+	})
+
+	t.Run("Should return error for unknown pack", func(t *testing.T) {
+		mockUserConfigDir(t)
+
+		if _, err := findPack("", "cobol"); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Should return error for missing templates directory", func(t *testing.T) {
+		if _, err := findPack(filepath.Join(t.TempDir(), "missing"), "go"); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestRenderingBuiltinPacks(t *testing.T) {
+	mockUserConfigDir(t)
+
+	expected := map[string][]string{
+		"python": {"README.md", "solution.py", "test_solution.py"},
+		"rust":   {"Cargo.toml", "README.md", filepath.Join("src", "main.rs")},
+	}
+
+	for name, files := range expected {
+		t.Run("Should render "+name, func(t *testing.T) {
+			pack, err := findPack("", name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			target := t.TempDir()
+			created, err := renderTemplates(pack, target, mockScaffoldData())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(created) != len(files) {
+				t.Fatalf("Expected %v, got %v", files, created)
+			}
+			for i, file := range files {
+				if created[i] != filepath.Join(target, file) {
+					t.Errorf("Expected %s, got %s", file, created[i])
+				}
+			}
+		})
+	}
+}
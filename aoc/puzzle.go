@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	titlePattern         = regexp.MustCompile(`<h2[^>]*>--- (Day \d+: .*?) ---</h2>`)
+	articlePattern       = regexp.MustCompile(`(?s)<article class="day-desc">(.*?)</article>`)
+	examplePattern       = regexp.MustCompile(`(?s)<pre><code>(.*?)</code></pre>`)
+	exampleAnswerPattern = regexp.MustCompile(`<code><em>([^<]*)</em></code>`)
+)
+
+// Puzzle is what the scaffolding needs from a puzzle page
+type Puzzle struct {
+	Year  int
+	Day   int
+	URL   string
+	Title string
+	// Example is the first example input of part 1, if there is one
+	Example string
+	// ExampleAnswer is the last highlighted answer in part 1, usually the answer for the example
+	ExampleAnswer string
+}
+
+func puzzleURL(year, day int) string {
+	return fmt.Sprintf("%s/%d/day/%d", baseURL(), year, day)
+}
+
+func inputURL(year, day int) string {
+	return puzzleURL(year, day) + "/input"
+}
+
+func puzzlePagePath(year, day int) (string, error) {
+	return cachePath("puzzles", fmt.Sprintf("%d-%d.html", year, day))
+}
+
+func pageText(content string) string {
+	return html.UnescapeString(tagPattern.ReplaceAllString(content, ""))
+}
+
+// parsePuzzlePage reads the title, example and example answer from a puzzle page
+func parsePuzzlePage(year, day int, page string) Puzzle {
+	puzzle := Puzzle{Year: year, Day: day, URL: puzzleURL(year, day), Title: fmt.Sprintf("Day %d", day)}
+
+	if match := titlePattern.FindStringSubmatch(page); match != nil {
+		puzzle.Title = pageText(match[1])
+	}
+
+	article := articlePattern.FindStringSubmatch(page)
+	if article == nil {
+		return puzzle
+	}
+
+	if match := examplePattern.FindStringSubmatch(article[1]); match != nil {
+		puzzle.Example = pageText(match[1])
+	}
+
+	if matches := exampleAnswerPattern.FindAllStringSubmatch(article[1], -1); len(matches) > 0 {
+		puzzle.ExampleAnswer = pageText(matches[len(matches)-1][1])
+	}
+
+	return puzzle
+}
+
+// savePuzzlePage keeps the puzzle page in the cache
+func savePuzzlePage(year, day int, page []byte) error {
+	path, err := puzzlePagePath(year, day)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, page, 0o644)
+}
+
+// loadPuzzlePage reads the saved puzzle page for a day
+func loadPuzzlePage(year, day int) (page []byte, err error) {
+	path, err := puzzlePagePath(year, day)
+	if err != nil {
+		return page, err
+	}
+	return readFile(path)
+}
+
+// fetchPuzzle fetches and saves the puzzle page for a day, offline it reads the saved page instead
+func fetchPuzzle(year, day int, cookie http.Cookie) (puzzle Puzzle, err error) {
+	var page []byte
+	if isOffline() {
+		page, err = loadPuzzlePage(year, day)
+		if errors.Is(err, os.ErrNotExist) {
+			return puzzle, withCode(codeOffline, fmt.Errorf("The puzzle page of %d day %d was never saved", year, day))
+		}
+		if err != nil {
+			return puzzle, err
+		}
+	} else {
+		if page, err = fetchPage(puzzleURL(year, day), cookie); err != nil {
+			return puzzle, err
+		}
+
+		if err := savePuzzlePage(year, day, page); err != nil {
+			return puzzle, err
+		}
+	}
+
+	if !strings.Contains(string(page), "--- Day") {
+		return puzzle, fmt.Errorf("%s is not a puzzle page", puzzleURL(year, day))
+	}
+
+	return parsePuzzlePage(year, day, string(page)), nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"testing"
+)
+
+const mockPuzzlePage = `<main>
+<article class="day-desc"><h2>--- Day 5: Supply Stacks ---</h2><p>The expedition can depart as soon as the final supplies have been unloaded.</p>
+<p>For example:</p>
+<pre><code>    [D]    
+[N] [C]    
+[Z] [M] [P]
+ 1   2   3 
+
+move 1 from 2 to 1
+</code></pre>
+<p>The Elves just need to know <em>which crate</em> will end up on top. In this example, the top crates are <code>C</code> in stack 1 and <code>Z</code> in stack 3, so you should combine these together and give the Elves the message <code><em>CMZ</em></code>.</p>
+</article>
+</main>`
+
+func TestParsingPuzzlePage(t *testing.T) {
+	puzzle := parsePuzzlePage(2022, 5, mockPuzzlePage)
+
+	if puzzle.Title != "Day 5: Supply Stacks" {
+		t.Errorf("Expected title Day 5: Supply Stacks, got %s", puzzle.Title)
+	}
+	if puzzle.URL != "https://adventofcode.com/2022/day/5" {
+		t.Errorf("Expected the puzzle url, got %s", puzzle.URL)
+	}
+
+	expectedExample := "    [D]    \n[N] [C]    \n[Z] [M] [P]\n 1   2   3 \n\nmove 1 from 2 to 1\n"
+	if puzzle.Example != expectedExample {
+		t.Errorf("Expected example %q, got %q", expectedExample, puzzle.Example)
+	}
+	if puzzle.ExampleAnswer != "CMZ" {
+		t.Errorf("Expected example answer CMZ, got %s", puzzle.ExampleAnswer)
+	}
+}
+
+func TestParsingPuzzlePageWithoutExample(t *testing.T) {
+	puzzle := parsePuzzlePage(2022, 5, "<main></main>")
+
+	if puzzle.Title != "Day 5" || puzzle.Example != "" || puzzle.ExampleAnswer != "" {
+		t.Errorf("Expected only a default title, got %+v", puzzle)
+	}
+}
+
+func TestFetchingPuzzle(t *testing.T) {
+	mockCacheDir(t)
+	client = &mockPageClient{pages: map[string]string{"/2022/day/5": mockPuzzlePage}}
+	cookie := http.Cookie{
+		Name:  "session",
+		Value: "abc123",
+	}
+
+	puzzle, err := fetchPuzzle(2022, 5, cookie)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if puzzle.ExampleAnswer != "CMZ" {
+		t.Errorf("Expected example answer CMZ, got %s", puzzle.ExampleAnswer)
+	}
+
+	path, _ := puzzlePagePath(2022, 5)
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the puzzle page to be saved, got error: %s", err.Error())
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+)
+
+// error codes of -json results, scripts can rely on them not changing
+const (
+	codeError           = "error"
+	codeUsage           = "usage"
+	codeSession         = "session"
+	codeInvalidPuzzle   = "invalid_puzzle"
+	codeLocked          = "locked"
+	codeNetwork         = "network"
+	codeHTTPStatus      = "http_status"
+	codeSuspiciousInput = "suspicious_input"
+	codeExists          = "exists"
+	codeInputModified   = "input_modified"
+	codeNotFound        = "not_found"
+	codeIO              = "io"
+	codeWrongAnswer     = "wrong_answer"
+	codeRateLimited     = "rate_limited"
+	codeCheckFailed     = "check_failed"
+	codeOffline         = "offline"
+	codeExit            = "exit"
+)
+
+// exit codes of aoc, scripts can rely on them not changing
+const (
+	exitFailure     = 1
+	exitUsage       = 2
+	exitSession     = 3
+	exitLocked      = 4
+	exitRateLimited = 5
+	exitNetwork     = 6
+	exitIO          = 7
+	exitWrongAnswer = 8
+	exitCheckFailed = 9
+	exitOffline     = 10
+	exitCommand     = 11
+)
+
+// exitCodes are the exit codes of the error codes, any other error exits with exitFailure
+var exitCodes = map[string]int{
+	codeUsage:           exitUsage,
+	codeInvalidPuzzle:   exitUsage,
+	codeSession:         exitSession,
+	codeLocked:          exitLocked,
+	codeRateLimited:     exitRateLimited,
+	codeNetwork:         exitNetwork,
+	codeHTTPStatus:      exitNetwork,
+	codeSuspiciousInput: exitNetwork,
+	codeExists:          exitIO,
+	codeInputModified:   exitIO,
+	codeNotFound:        exitIO,
+	codeIO:              exitIO,
+	codeWrongAnswer:     exitWrongAnswer,
+	codeCheckFailed:     exitCheckFailed,
+	codeOffline:         exitOffline,
+	codeExit:            exitCommand,
+}
+
+// Result is what a command prints with -json
+type Result struct {
+	// Status is ok or error
+	Status  string `json:"status"`
+	Command string `json:"command"`
+	Year    int    `json:"year,omitempty"`
+	Day     int    `json:"day,omitempty"`
+	// Path is the file or directory the command wrote
+	Path  string `json:"path,omitempty"`
+	Bytes int    `json:"bytes,omitempty"`
+	// Code identifies the error, see the code constants
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Data is what the command would show as text, ie. a leaderboard's standings
+	Data interface{} `json:"data,omitempty"`
+}
+
+// codedError gives an error one of the -json result codes
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e codedError) Unwrap() error {
+	return e.err
+}
+
+func withCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return codedError{code: code, err: err}
+}
+
+// errorCode returns the -json result code of an error
+func errorCode(err error) string {
+	var coded codedError
+	var usageErr usageError
+	var exitErr exitError
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &coded):
+		return coded.code
+	case errors.As(err, &usageErr):
+		return codeUsage
+	case errors.As(err, &exitErr):
+		return codeExit
+	case errors.Is(err, errInputModified):
+		return codeInputModified
+	case errors.Is(err, fs.ErrNotExist):
+		return codeNotFound
+	case errors.As(err, &pathErr):
+		return codeIO
+	}
+	return codeError
+}
+
+// exitCode returns the exit code of aoc after a command returned err
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if code, ok := exitCodes[errorCode(err)]; ok {
+		return code
+	}
+	return exitFailure
+}
+
+// wantsJSON returns whether -json is in args, before the flags are parsed
+func wantsJSON(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return false
+		case "-json", "--json", "-json=true", "--json=true":
+			return true
+		}
+	}
+	return false
+}
+
+// writeResult writes the result of a command, which failed if err is not nil, as JSON
+func writeResult(w io.Writer, result Result, err error) error {
+	result.Status = "ok"
+	if err != nil {
+		result.Status = "error"
+		result.Code = errorCode(err)
+		result.Message = err.Error()
+	}
+	return json.NewEncoder(w).Encode(result)
+}
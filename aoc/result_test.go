@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{errors.New("failed"), codeError},
+		{withCode(codeSession, errors.New("No session cookie")), codeSession},
+		{fmt.Errorf("day 1: %w", withCode(codeWrongAnswer, errors.New("wrong"))), codeWrongAnswer},
+		{usageError("Please enter a url"), codeUsage},
+		{exitError{command: "false", code: 1}, codeExit},
+		{fmt.Errorf("inputs.txt: %w", errInputModified), codeInputModified},
+		{&os.PathError{Op: "open", Path: "session", Err: os.ErrNotExist}, codeNotFound},
+	}
+
+	for _, test := range tests {
+		if code := errorCode(test.err); code != test.expected {
+			t.Errorf("Expected %s for %q, got %s", test.expected, test.err, code)
+		}
+	}
+
+	if withCode(codeSession, nil) != nil {
+		t.Error("Expected no error")
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, 0},
+		{errors.New("failed"), exitFailure},
+		{usageError("Please enter a url"), exitUsage},
+		{withCode(codeInvalidPuzzle, errors.New("Invalid year: 2014")), exitUsage},
+		{withCode(codeSession, errors.New("No session cookie")), exitSession},
+		{withCode(codeLocked, errors.New("It is not December yet")), exitLocked},
+		{withCode(codeRateLimited, errors.New("too recent")), exitRateLimited},
+		{withCode(codeNetwork, errors.New("connection refused")), exitNetwork},
+		{withCode(codeExists, errors.New("inputs.txt already exists")), exitIO},
+		{&os.PathError{Op: "open", Path: "inputs.txt", Err: os.ErrPermission}, exitIO},
+		{withCode(codeWrongAnswer, errors.New("wrong")), exitWrongAnswer},
+		{withCode(codeCheckFailed, errors.New("1 answers no longer match")), exitCheckFailed},
+		{exitError{command: "go run .", code: 3}, exitCommand},
+	}
+
+	for _, test := range tests {
+		if code := exitCode(test.err); code != test.expected {
+			t.Errorf("Expected %d for %v, got %d", test.expected, test.err, code)
+		}
+	}
+}
+
+func TestWantingJSON(t *testing.T) {
+	if !wantsJSON([]string{"fetch", "-json", "2022/1"}) || !wantsJSON([]string{"--json", "bench"}) {
+		t.Error("Expected -json to be found")
+	}
+	if wantsJSON([]string{"fetch", "2022/1"}) || wantsJSON([]string{"run", "--", "-json"}) {
+		t.Error("Expected -json not to be found")
+	}
+}
+
+func TestWritingResults(t *testing.T) {
+	readFile = os.ReadFile
+	chdir(t)
+
+	run := func(args ...string) (result Result, stderr string, code int) {
+		var stdout, errOutput bytes.Buffer
+		code = execute(args, &stdout, &errOutput)
+		if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+			t.Fatalf("Expected a JSON result, got %q: %s", stdout.String(), err)
+		}
+		return result, errOutput.String(), code
+	}
+
+	t.Run("Should write ok results", func(t *testing.T) {
+		result, stderr, code := run("-json", "help")
+		if code != 0 || result.Status != "ok" || result.Command != "help" || result.Code != "" {
+			t.Errorf("Unexpected result %+v", result)
+		}
+		if stderr == "" {
+			t.Error("Expected the help on stderr")
+		}
+	})
+
+	t.Run("Should write error results", func(t *testing.T) {
+		result, _, code := run("fetch", "-json", "https://example.com/2022/day/1")
+		if code != exitUsage || result.Status != "error" || result.Code != codeInvalidPuzzle || result.Message == "" {
+			t.Errorf("Unexpected result %+v", result)
+		}
+	})
+
+	t.Run("Should write usage errors", func(t *testing.T) {
+		result, _, code := run("-json", "fetch", "-o", "-", "2022/1")
+		if code != 2 || result.Code != codeUsage || result.Command != "fetch" {
+			t.Errorf("Unexpected result %+v", result)
+		}
+
+		result, _, code = run("-json", "fetc")
+		if code != 2 || result.Code != codeUsage || result.Command != "" {
+			t.Errorf("Unexpected result %+v", result)
+		}
+	})
+}
+
+func TestDataKeys(t *testing.T) {
+	keyPattern := regexp.MustCompile(`"([^"]+)":`)
+	solve := &Solve{Member: Member{ID: 1}, Duration: time.Minute}
+	for _, data := range []interface{}{
+		[]Standing{{Member: Member{ID: 1}, Score: "1"}},
+		[]DayStats{{Day: 1, FastestPart1: solve, FastestPart2: solve, FastestDelta: solve}},
+		[]Snapshot{{FetchedAt: time.Unix(0, 0)}},
+		[]ReplayStar{{Member: Member{ID: 1}, Part: 1}},
+		YearStats{Year: 2022, Days: []DayResult{{Day: 1, Part1: PartResult{Solved: true, Time: "00:01:00", Rank: 1, Score: 100}}}},
+	} {
+		content, err := json.Marshal(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range keyPattern.FindAllStringSubmatch(string(content), -1) {
+			if match[1] != strings.ToLower(match[1]) {
+				t.Errorf("Expected snake_case keys, got %s in %s", match[1], content)
+			}
+		}
+	}
+}
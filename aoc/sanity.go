@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// maxInputSize is well above the largest input Advent of Code has served
+const maxInputSize = 1 << 20
+
+var (
+	htmlPattern = regexp.MustCompile(`(?i)<\s*(!doctype|html|head|body|script|div|p|a)[\s>]`)
+
+	// error pages that adventofcode.com serves with a 200 status, and their error codes
+	aocErrorMessages = []struct {
+		message string
+		code    string
+	}{
+		{"Please don't repeatedly request this endpoint before it unlocks!", codeLocked},
+		{"Puzzle inputs differ by user.  Please log in to get your puzzle input.", codeSession},
+		{"Puzzle inputs differ by user. Please log in to get your puzzle input.", codeSession},
+		{"404 Not Found", codeSuspiciousInput},
+		{"500 Internal Server Error", codeSuspiciousInput},
+	}
+)
+
+// readInput reads the body of an input response, refusing to read past maxInputSize.
+// A larger body is an error even with -allow-suspicious, as only part of it was read.
+func readInput(body io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(body, maxInputSize+1))
+	if err != nil {
+		return content, err
+	}
+	if len(content) > maxInputSize {
+		return content, withCode(codeSuspiciousInput, fmt.Errorf("Input is larger than %d bytes", maxInputSize))
+	}
+	return content, nil
+}
+
+// checkInput rejects content that does not look like a puzzle input and makes sure it ends with a newline.
+// When allowSuspicious is set, only the trailing newline is normalized.
+func checkInput(content []byte, allowSuspicious bool) ([]byte, error) {
+	if !allowSuspicious {
+		if err := inspectInput(content); err != nil {
+			return content, fmt.Errorf("%w (use -allow-suspicious to save it anyway)", err)
+		}
+	}
+
+	return normalizeNewline(content), nil
+}
+
+func inspectInput(content []byte) error {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return withCode(codeSuspiciousInput, errors.New("Input is empty"))
+	}
+
+	if len(content) > maxInputSize {
+		return withCode(codeSuspiciousInput, fmt.Errorf("Input is larger than %d bytes", maxInputSize))
+	}
+
+	for _, aocError := range aocErrorMessages {
+		if bytes.Contains(content, []byte(aocError.message)) {
+			return withCode(aocError.code, fmt.Errorf("Advent of Code returned an error: %s", aocError.message))
+		}
+	}
+
+	if htmlPattern.Match(content) {
+		return withCode(codeSuspiciousInput, errors.New("Input looks like an HTML page"))
+	}
+
+	return nil
+}
+
+// statusError explains an unsuccessful response, by advent of code's message when the body has one,
+// ie. a puzzle that is not unlocked yet, or else by its status
+func statusError(action string, res *http.Response, body []byte) error {
+	for _, aocError := range aocErrorMessages {
+		if aocError.code != codeSuspiciousInput && bytes.Contains(body, []byte(aocError.message)) {
+			return withCode(aocError.code, fmt.Errorf("Advent of Code returned an error: %s", aocError.message))
+		}
+	}
+	return withCode(statusCode(res.StatusCode), fmt.Errorf("Could not %s: %s", action, res.Status))
+}
+
+func normalizeNewline(content []byte) []byte {
+	if len(content) == 0 || content[len(content)-1] == '\n' {
+		return content
+	}
+	return append(content, '\n')
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCheckingInput(t *testing.T) {
+	t.Run("Should accept a regular input", func(t *testing.T) {
+		input := []byte("1000\n2000\n\n3000\n")
+
+		content, err := checkInput(input, false)
+		if err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+		if !bytes.Equal(content, input) {
+			t.Errorf("Expected %q, got %q", input, content)
+		}
+	})
+
+	t.Run("Should add a missing trailing newline", func(t *testing.T) {
+		content, err := checkInput([]byte("1000\n2000"), false)
+		if err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+		if string(content) != "1000\n2000\n" {
+			t.Errorf("Expected content to end with a newline, got %q", content)
+		}
+	})
+
+	t.Run("Should return error for empty input", func(t *testing.T) {
+		if _, err := checkInput([]byte(" \n"), false); err == nil {
+			t.Error("Expected an error for empty input")
+		}
+	})
+
+	t.Run("Should return error for html", func(t *testing.T) {
+		input := []byte("<!DOCTYPE html>\n<html lang=\"en-us\"><body>Login</body></html>")
+
+		if _, err := checkInput(input, false); err == nil {
+			t.Error("Expected an error for html input")
+		}
+	})
+
+	t.Run("Should return error for advent of code error messages", func(t *testing.T) {
+		input := []byte("Please don't repeatedly request this endpoint before it unlocks! The calendar countdown is synchronized with the server time; the link will be enabled on the calendar the instant this puzzle becomes available.\n")
+
+		_, err := checkInput(input, false)
+		if err == nil {
+			t.Fatal("Expected an error for unlock message")
+		}
+		if code := errorCode(err); code != codeLocked {
+			t.Errorf("Expected %s error, got %s", codeLocked, code)
+		}
+	})
+
+	t.Run("Should return session error for login message", func(t *testing.T) {
+		input := []byte("Puzzle inputs differ by user.  Please log in to get your puzzle input.\n")
+
+		if _, err := checkInput(input, false); errorCode(err) != codeSession {
+			t.Errorf("Expected %s error, got %v", codeSession, err)
+		}
+	})
+
+	t.Run("Should return error for oversized input", func(t *testing.T) {
+		input := []byte(strings.Repeat("1\n", maxInputSize))
+
+		if _, err := checkInput(input, false); err == nil {
+			t.Error("Expected an error for oversized input")
+		}
+	})
+
+	t.Run("Should allow suspicious input when asked", func(t *testing.T) {
+		input := []byte("<html></html>")
+
+		content, err := checkInput(input, true)
+		if err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+		if string(content) != "<html></html>\n" {
+			t.Errorf("Expected normalized content, got %q", content)
+		}
+	})
+}
+
+func TestReadingInput(t *testing.T) {
+	input := strings.Repeat("a", maxInputSize+10)
+
+	content, err := readInput(strings.NewReader(input))
+	if errorCode(err) != codeSuspiciousInput {
+		t.Errorf("Expected %s error, got %v", codeSuspiciousInput, err)
+	}
+	if len(content) != maxInputSize+1 {
+		t.Errorf("Expected to read %d bytes, read %d", maxInputSize+1, len(content))
+	}
+
+	if content, err := readInput(strings.NewReader("1\n")); err != nil || string(content) != "1\n" {
+		t.Errorf("Expected the input, got %q, %v", content, err)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	inputFileName   = "inputs.txt"
+	exampleFileName = "example.txt"
+	templateExt     = ".tmpl"
+)
+
+var userConfigDir = os.UserConfigDir
+
+// scaffoldData is what templates are rendered with
+type scaffoldData struct {
+	Puzzle
+	InputFile   string
+	ExampleFile string
+	Run         string
+	Test        string
+}
+
+// dayDir is where a day's solution is scaffolded, ie. 2022/day05
+func dayDir(year, day int) string {
+	return filepath.Join(strconv.Itoa(year), fmt.Sprintf("day%02d", day))
+}
+
+// renderTemplates renders the pack's templates into the target directory without the .tmpl extension.
+// Files that already exist are left alone.
+func renderTemplates(pack Pack, target string, data interface{}) (created []string, err error) {
+	templates := pack.templates
+	err = fs.WalkDir(templates, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && name != "." && pack.topLevel {
+			return fs.SkipDir
+		}
+		if entry.IsDir() || !pack.renders(name) {
+			return nil
+		}
+
+		output := filepath.Join(target, filepath.FromSlash(strings.TrimSuffix(name, templateExt)))
+		if exists, err := checkFileExist(output); err != nil || exists {
+			return err
+		}
+
+		tmpl, err := template.New(path.Base(name)).ParseFS(templates, name)
+		if err != nil {
+			return err
+		}
+
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, data); err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(output, rendered.Bytes(), 0o644); err != nil {
+			return err
+		}
+
+		created = append(created, output)
+		return nil
+	})
+	return created, err
+}
+
+// writeIfMissing writes the file unless it already exists, returning whether it was written
+func writeIfMissing(name string, content []byte) (bool, error) {
+	exists, err := checkFileExist(name)
+	if err != nil || exists {
+		return false, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(name, content, 0o644)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func mockScaffoldData() scaffoldData {
+	return scaffoldData{
+		Puzzle:      parsePuzzlePage(2022, 5, mockPuzzlePage),
+		InputFile:   inputFileName,
+		ExampleFile: exampleFileName,
+	}
+}
+
+func mockUserConfigDir(t *testing.T) string {
+	dir := t.TempDir()
+	userConfigDir = func() (string, error) {
+		return dir, nil
+	}
+	return dir
+}
+
+func TestRenderingBuiltinTemplates(t *testing.T) {
+	mockUserConfigDir(t)
+	target := t.TempDir()
+
+	pack, err := findPack("", "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := renderTemplates(pack, target, mockScaffoldData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 3 {
+		t.Fatalf("Expected main.go, main_test.go and README.md, got %v", created)
+	}
+
+	for _, name := range []string{"main.go", "main_test.go"} {
+		if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(target, name), nil, 0); err != nil {
+			t.Errorf("Expected %s to be valid Go, got error: %s", name, err.Error())
+		}
+	}
+
+	test, _ := os.ReadFile(filepath.Join(target, "main_test.go"))
+	if !strings.Contains(string(test), `expected := "CMZ"`) {
+		t.Errorf("Expected main_test.go to check the example answer, got:\n%s", test)
+	}
+
+	readme, _ := os.ReadFile(filepath.Join(target, "README.md"))
+	if !strings.HasPrefix(string(readme), "# Day 5: Supply Stacks") {
+		t.Errorf("Expected README.md to have the puzzle title, got:\n%s", readme)
+	}
+}
+
+func TestRenderingUserTemplates(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "solution.py.tmpl"), []byte("# {{.Title}}\n"), 0o644)
+	target := t.TempDir()
+	os.WriteFile(filepath.Join(target, "kept.txt"), []byte("mine"), 0o644)
+	os.WriteFile(filepath.Join(dir, "kept.txt.tmpl"), []byte("theirs"), 0o644)
+	os.MkdirAll(filepath.Join(dir, "kotlin"), 0o755)
+	os.WriteFile(filepath.Join(dir, "kotlin", "Main.kt.tmpl"), []byte("// {{.Title}}\n"), 0o644)
+
+	pack, err := findPack(dir, "custom")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := renderTemplates(pack, target, mockScaffoldData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 {
+		t.Errorf("Expected only solution.py to be created, got %v", created)
+	}
+
+	solution, _ := os.ReadFile(filepath.Join(target, "solution.py"))
+	if string(solution) != "# Day 5: Supply Stacks\n" {
+		t.Errorf("Expected rendered template, got %q", solution)
+	}
+
+	if _, err := os.Stat(filepath.Join(target, "kotlin")); err == nil {
+		t.Error("Should not render the templates of other packs")
+	}
+
+	kept, _ := os.ReadFile(filepath.Join(target, "kept.txt"))
+	if string(kept) != "mine" {
+		t.Error("Should not overwrite existing files")
+	}
+}
+
+func TestDayDir(t *testing.T) {
+	if dir := dayDir(2022, 5); dir != filepath.Join("2022", "day05") {
+		t.Errorf("Expected 2022/day05, got %s", dir)
+	}
+}
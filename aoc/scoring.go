@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var scoringModes = []string{"local", "stars", "time", "delta", "fair"}
+
+// Standing is a member's place on the leaderboard under a scoring mode
+type Standing struct {
+	Member Member `json:"member"`
+	Score  string `json:"score"`
+}
+
+// DayStats summarizes how a leaderboard did on a single day
+type DayStats struct {
+	Day          int    `json:"day"`
+	Part1        int    `json:"part1"`
+	Part2        int    `json:"part2"`
+	FastestPart1 *Solve `json:"fastest_part1,omitempty"`
+	FastestPart2 *Solve `json:"fastest_part2,omitempty"`
+	FastestDelta *Solve `json:"fastest_delta,omitempty"`
+}
+
+// Solve is how long a member took, measured from the puzzle unlocking or from their part 1
+type Solve struct {
+	Member   Member        `json:"member"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// memberScore is the value a member is ranked by, ok is false when they have nothing to rank
+type memberScore struct {
+	member Member
+	value  time.Duration
+	ok     bool
+}
+
+func leaderboardYear(leaderboard Leaderboard) (int, error) {
+	year, err := strconv.Atoi(leaderboard.Event)
+	if err != nil {
+		return year, fmt.Errorf("%s is not a valid event", leaderboard.Event)
+	}
+	return year, nil
+}
+
+// solveDuration returns how long after unlocking the member solved the part of the day
+func solveDuration(member Member, year, day, part int, est *time.Location) (time.Duration, bool) {
+	solvedAt, ok := member.Solved(day, part)
+	if !ok {
+		return 0, false
+	}
+	return solvedAt.Sub(unlockTime(year, day, est)), true
+}
+
+// partDelta returns how long the member took to solve part 2 after solving part 1
+func partDelta(member Member, day int) (time.Duration, bool) {
+	first, ok := member.Solved(day, 1)
+	if !ok {
+		return 0, false
+	}
+	second, ok := member.Solved(day, 2)
+	if !ok {
+		return 0, false
+	}
+	return second.Sub(first), true
+}
+
+// lastSolveDuration returns the time to the member's last solved part of the day
+func lastSolveDuration(member Member, year, day int, est *time.Location) (time.Duration, bool) {
+	if duration, ok := solveDuration(member, year, day, 2, est); ok {
+		return duration, true
+	}
+	return solveDuration(member, year, day, 1, est)
+}
+
+func totalTime(member Member, year int, est *time.Location) memberScore {
+	score := memberScore{member: member}
+	for day := 1; day <= lastDay; day++ {
+		if duration, ok := lastSolveDuration(member, year, day, est); ok {
+			score.value += duration
+			score.ok = true
+		}
+	}
+	return score
+}
+
+func medianDelta(member Member) memberScore {
+	var deltas []time.Duration
+	for day := 1; day <= lastDay; day++ {
+		if delta, ok := partDelta(member, day); ok {
+			deltas = append(deltas, delta)
+		}
+	}
+	if len(deltas) == 0 {
+		return memberScore{member: member}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i] < deltas[j] })
+	middle := len(deltas) / 2
+	if len(deltas)%2 == 0 {
+		return memberScore{member: member, value: (deltas[middle-1] + deltas[middle]) / 2, ok: true}
+	}
+	return memberScore{member: member, value: deltas[middle], ok: true}
+}
+
+// firstOpen estimates when a member starts on a puzzle each day, as the time after unlocking of the earliest
+// part 1 they have ever solved. Someone who always starts in the morning their time is not penalized for
+// the hours they were asleep after midnight ETC/UTC-5.
+func firstOpen(member Member, year int, est *time.Location) (offset time.Duration, ok bool) {
+	for day := 1; day <= lastDay; day++ {
+		duration, solved := solveDuration(member, year, day, 1, est)
+		if !solved {
+			continue
+		}
+		duration = duration % (24 * time.Hour)
+		if !ok || duration < offset {
+			offset, ok = duration, true
+		}
+	}
+	return offset, ok
+}
+
+// fairTime is the member's total time measured from their first open each day instead of from unlocking
+func fairTime(member Member, year int, est *time.Location) memberScore {
+	score := memberScore{member: member}
+	offset, ok := firstOpen(member, year, est)
+	if !ok {
+		return score
+	}
+
+	for day := 1; day <= lastDay; day++ {
+		duration, solved := lastSolveDuration(member, year, day, est)
+		if !solved {
+			continue
+		}
+		if duration -= offset; duration > 0 {
+			score.value += duration
+		}
+		score.ok = true
+	}
+	return score
+}
+
+// sortByDuration ranks members by the shortest duration, after stars when byStars is set
+func sortByDuration(scores []memberScore, byStars bool) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		a, b := scores[i], scores[j]
+		if a.ok != b.ok {
+			return a.ok
+		}
+		if byStars && a.member.Stars != b.member.Stars {
+			return a.member.Stars > b.member.Stars
+		}
+		return a.value < b.value
+	})
+}
+
+// scoreMembers ranks the leaderboard's members with one of the scoring modes
+func scoreMembers(leaderboard Leaderboard, mode string, est *time.Location) (standings []Standing, err error) {
+	year, err := leaderboardYear(leaderboard)
+	if err != nil {
+		return standings, err
+	}
+
+	members := rankMembers(leaderboard)
+	scores := make([]memberScore, len(members))
+	byStars := true
+
+	switch mode {
+	case "local":
+		return localStandings(leaderboard), nil
+	case "stars":
+		sort.SliceStable(members, func(i, j int) bool { return members[i].Stars > members[j].Stars })
+		for _, member := range members {
+			standings = append(standings, Standing{Member: member, Score: strconv.Itoa(member.Stars)})
+		}
+		return standings, nil
+	case "time":
+		for i, member := range members {
+			scores[i] = totalTime(member, year, est)
+		}
+	case "delta":
+		for i, member := range members {
+			scores[i] = medianDelta(member)
+		}
+		byStars = false
+	case "fair":
+		for i, member := range members {
+			scores[i] = fairTime(member, year, est)
+		}
+	default:
+		return standings, fmt.Errorf("%s is not a scoring mode, use one of: %s", mode, strings.Join(scoringModes, ", "))
+	}
+
+	sortByDuration(scores, byStars)
+	for _, score := range scores {
+		standing := Standing{Member: score.member, Score: "-"}
+		if score.ok {
+			standing.Score = formatDuration(score.value)
+		}
+		standings = append(standings, standing)
+	}
+	return standings, nil
+}
+
+// localStandings ranks members by the site's local score
+func localStandings(leaderboard Leaderboard) (standings []Standing) {
+	for _, member := range rankMembers(leaderboard) {
+		standings = append(standings, Standing{Member: member, Score: strconv.Itoa(member.LocalScore)})
+	}
+	return standings
+}
+
+// dayStats breaks the leaderboard down by day, for every day anyone has solved
+func dayStats(leaderboard Leaderboard, est *time.Location) (stats []DayStats, err error) {
+	year, err := leaderboardYear(leaderboard)
+	if err != nil {
+		return stats, err
+	}
+
+	members := rankMembers(leaderboard)
+	for day := 1; day <= lastDay; day++ {
+		dayStat := DayStats{Day: day}
+		for _, member := range members {
+			if duration, ok := solveDuration(member, year, day, 1, est); ok {
+				dayStat.Part1++
+				dayStat.FastestPart1 = fasterSolve(dayStat.FastestPart1, member, duration)
+			}
+			if duration, ok := solveDuration(member, year, day, 2, est); ok {
+				dayStat.Part2++
+				dayStat.FastestPart2 = fasterSolve(dayStat.FastestPart2, member, duration)
+			}
+			if delta, ok := partDelta(member, day); ok {
+				dayStat.FastestDelta = fasterSolve(dayStat.FastestDelta, member, delta)
+			}
+		}
+		if dayStat.Part1 > 0 {
+			stats = append(stats, dayStat)
+		}
+	}
+	return stats, nil
+}
+
+func fasterSolve(fastest *Solve, member Member, duration time.Duration) *Solve {
+	if fastest == nil || duration < fastest.Duration {
+		return &Solve{Member: member, Duration: duration}
+	}
+	return fastest
+}
+
+// formatDuration formats durations the way the site does, as hh:mm:ss, with days once it is over 24 hours
+func formatDuration(duration time.Duration) string {
+	duration = duration.Round(time.Second)
+	days := duration / (24 * time.Hour)
+	duration -= days * 24 * time.Hour
+	hours := duration / time.Hour
+	duration -= hours * time.Hour
+	minutes := duration / time.Minute
+	seconds := (duration - minutes*time.Minute) / time.Second
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %02d:%02d:%02d", days, hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+}
+
+func formatSolve(solve *Solve) string {
+	if solve == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", solve.Member.DisplayName(), formatDuration(solve.Duration))
+}
+
+// renderStandings writes the scored leaderboard as a table
+func renderStandings(w io.Writer, standings []Standing) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Rank\tScore\tStars\tDays\tName")
+
+	for i, standing := range standings {
+		member := standing.Member
+		fmt.Fprintf(table, "%d)\t%s\t%d\t%s\t%s\n", i+1, standing.Score, member.Stars, starRow(member), member.DisplayName())
+	}
+
+	return table.Flush()
+}
+
+// renderDayStats writes the per day breakdown as a table
+func renderDayStats(w io.Writer, stats []DayStats) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Day\tPart 1\tPart 2\tFastest part 1\tFastest part 2\tFastest part 2 delta")
+
+	for _, stat := range stats {
+		fmt.Fprintf(table, "%d\t%d\t%d\t%s\t%s\t%s\n", stat.Day, stat.Part1, stat.Part2,
+			formatSolve(stat.FastestPart1), formatSolve(stat.FastestPart2), formatSolve(stat.FastestDelta))
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func mockEventLocation(t *testing.T) *time.Location {
+	est, err := eventLocation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return est
+}
+
+func TestScoringMembers(t *testing.T) {
+	leaderboard := mockLeaderboard(t)
+	est := mockEventLocation(t)
+
+	tests := []struct {
+		mode     string
+		ranking  []int
+		topScore string
+	}{
+		{mode: "local", ranking: []int{1, 2, 3}, topScore: "11"},
+		{mode: "stars", ranking: []int{1, 2, 3}, topScore: "4"},
+		{mode: "time", ranking: []int{1, 2, 3}, topScore: "00:56:40"},
+		{mode: "delta", ranking: []int{1, 2, 3}, topScore: "00:20:50"},
+		{mode: "fair", ranking: []int{1, 2, 3}, topScore: "00:46:40"},
+	}
+
+	for _, test := range tests {
+		t.Run("Should rank by "+test.mode, func(t *testing.T) {
+			standings, err := scoreMembers(leaderboard, test.mode, est)
+			if err != nil {
+				t.Fatalf("Should not have error, got error: %s", err.Error())
+			}
+
+			for i, id := range test.ranking {
+				if standings[i].Member.ID != id {
+					t.Errorf("Expected member %d at rank %d, got %d", id, i+1, standings[i].Member.ID)
+				}
+			}
+			if standings[0].Score != test.topScore {
+				t.Errorf("Expected top score %s, got %s", test.topScore, standings[0].Score)
+			}
+		})
+	}
+
+	t.Run("Should not score members without a solve", func(t *testing.T) {
+		standings, _ := scoreMembers(leaderboard, "delta", est)
+
+		if standings[2].Score != "-" {
+			t.Errorf("Expected no score for member without part 2, got %s", standings[2].Score)
+		}
+	})
+
+	t.Run("Should return error for unknown mode", func(t *testing.T) {
+		if _, err := scoreMembers(leaderboard, "fastest", est); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestDayStats(t *testing.T) {
+	stats, err := dayStats(mockLeaderboard(t), mockEventLocation(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("Expected stats for 2 days, got %d", len(stats))
+	}
+
+	first := stats[0]
+	if first.Part1 != 3 || first.Part2 != 2 {
+		t.Errorf("Expected 3 part 1 and 2 part 2 completions, got %d and %d", first.Part1, first.Part2)
+	}
+	if first.FastestPart1.Member.Name != "bob" || first.FastestPart1.Duration != 0 {
+		t.Errorf("Expected bob to be fastest on part 1, got %s", formatSolve(first.FastestPart1))
+	}
+	if first.FastestPart2.Member.Name != "alice" || first.FastestPart2.Duration != 10*time.Minute {
+		t.Errorf("Expected alice to be fastest on part 2, got %s", formatSolve(first.FastestPart2))
+	}
+	if first.FastestDelta.Member.Name != "alice" || first.FastestDelta.Duration != 5*time.Minute {
+		t.Errorf("Expected alice to have the fastest delta, got %s", formatSolve(first.FastestDelta))
+	}
+}
+
+func TestFormattingDuration(t *testing.T) {
+	t.Run("Should format as hours, minutes and seconds", func(t *testing.T) {
+		if formatted := formatDuration(90*time.Minute + 5*time.Second); formatted != "01:30:05" {
+			t.Errorf("Expected 01:30:05, got %s", formatted)
+		}
+	})
+
+	t.Run("Should include days", func(t *testing.T) {
+		if formatted := formatDuration(49 * time.Hour); formatted != "2d 01:00:00" {
+			t.Errorf("Expected 2d 01:00:00, got %s", formatted)
+		}
+	})
+}
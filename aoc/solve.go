@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultAnswerPattern matches the "Part 1: answer" lines the built in templates print
+const defaultAnswerPattern = `(?m)^Part {part}: *(.+?)\s*$`
+
+// Answer is a solution's answer to a part
+type Answer struct {
+	Part   int    `json:"part"`
+	Answer string `json:"answer"`
+}
+
+// extractAnswer finds the answer for a part in a solution's output. {part} in the pattern is replaced
+// with the part, and the answer is the pattern's first group, or the whole match without one.
+func extractAnswer(output, pattern string, part int) (answer string, err error) {
+	re, err := regexp.Compile(strings.ReplaceAll(pattern, "{part}", strconv.Itoa(part)))
+	if err != nil {
+		return answer, err
+	}
+
+	match := re.FindStringSubmatch(output)
+	if match == nil {
+		return answer, fmt.Errorf("No answer for part %d in the output", part)
+	}
+
+	answer = match[0]
+	if len(match) > 1 {
+		answer = match[1]
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return answer, fmt.Errorf("Answer for part %d is empty", part)
+	}
+	return answer, nil
+}
+
+// runSolution runs the pack's run command in the day's directory against its input and returns the output
+func runSolution(pack Pack, dir string) (output string, err error) {
+	if pack.Run == "" {
+		return output, fmt.Errorf("Template pack %s has no run command", pack.Name)
+	}
+
+	content, err := readFile(filepath.Join(dir, inputFileName))
+	if err != nil {
+		return output, err
+	}
+
+	return outputOf(dir, pack.Run, inputFileName, content)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractingAnswer(t *testing.T) {
+	output := "parsing input\nPart 1: 157\nPart 2: 70 \n"
+
+	t.Run("Should find the answer for each part", func(t *testing.T) {
+		for part, expected := range map[int]string{1: "157", 2: "70"} {
+			answer, err := extractAnswer(output, defaultAnswerPattern, part)
+			if err != nil {
+				t.Errorf("Should not have error, got error: %s", err.Error())
+			}
+			if answer != expected {
+				t.Errorf("Expected %s for part %d, got %s", expected, part, answer)
+			}
+		}
+	})
+
+	t.Run("Should use a custom pattern", func(t *testing.T) {
+		answer, err := extractAnswer("p1 -> 12\n", `p{part} -> \d+`, 1)
+		if err != nil {
+			t.Errorf("Should not have error, got error: %s", err.Error())
+		}
+		if answer != "p1 -> 12" {
+			t.Errorf("Expected the whole match without a group, got %s", answer)
+		}
+	})
+
+	t.Run("Should return error without an answer", func(t *testing.T) {
+		if _, err := extractAnswer("Part 1: \n", defaultAnswerPattern, 1); err == nil {
+			t.Error("Expected an error for an empty answer")
+		}
+		if _, err := extractAnswer("Part 1: 157\n", defaultAnswerPattern, 2); err == nil {
+			t.Error("Expected an error for a missing answer")
+		}
+	})
+}
+
+func TestRunningSolution(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is not available")
+	}
+	readFile = os.ReadFile
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, inputFileName), []byte("Part 1: 42\n"), 0o644)
+
+	t.Run("Should pass the input path", func(t *testing.T) {
+		output, err := runSolution(Pack{Name: "cat", Run: "cat {input}"}, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output != "Part 1: 42\n" {
+			t.Errorf("Expected the input as output, got %q", output)
+		}
+	})
+
+	t.Run("Should pipe the input", func(t *testing.T) {
+		output, err := runSolution(Pack{Name: "cat", Run: "cat"}, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output != "Part 1: 42\n" {
+			t.Errorf("Expected the input as output, got %q", output)
+		}
+	})
+
+	t.Run("Should return error without a run command", func(t *testing.T) {
+		if _, err := runSolution(Pack{Name: "empty"}, dir); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
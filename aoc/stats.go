@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var (
+	calendarDayPattern = regexp.MustCompile(`class="calendar-day(\d+)(?: (calendar-complete|calendar-verycomplete))?"`)
+	selfRowPattern     = regexp.MustCompile(`^\s*(\d+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s*$`)
+	tagPattern         = regexp.MustCompile(`<[^>]*>`)
+)
+
+// PartResult is the account's result for one part of a day, from /{year}/leaderboard/self
+type PartResult struct {
+	Solved bool   `json:"solved"`
+	Time   string `json:"time,omitempty"`
+	Rank   int    `json:"rank,omitempty"`
+	Score  int    `json:"score,omitempty"`
+}
+
+// DayResult is the account's stars and results for a day
+type DayResult struct {
+	Day   int        `json:"day"`
+	Stars int        `json:"stars"`
+	Part1 PartResult `json:"part1"`
+	Part2 PartResult `json:"part2"`
+}
+
+// YearStats is the account's results for an event, Days has days 1 to 25 in order
+type YearStats struct {
+	Year int         `json:"year"`
+	Days []DayResult `json:"days"`
+}
+
+// Day returns the result of a day, a day without a result has no stars
+func (s YearStats) Day(day int) DayResult {
+	if day < 1 || day > len(s.Days) {
+		return DayResult{Day: day}
+	}
+	return s.Days[day-1]
+}
+
+// Stars returns the total stars for the event
+func (s YearStats) Stars() (stars int) {
+	for _, day := range s.Days {
+		stars += day.Stars
+	}
+	return stars
+}
+
+func calendarURL(year int) string {
+	return fmt.Sprintf("%s/%d", baseURL(), year)
+}
+
+func selfLeaderboardURL(year int) string {
+	return fmt.Sprintf("%s/%d/leaderboard/self", baseURL(), year)
+}
+
+// parseCalendar reads the stars for each day from an event's calendar page
+func parseCalendar(page string) map[int]int {
+	stars := map[int]int{}
+	for _, match := range calendarDayPattern.FindAllStringSubmatch(page, -1) {
+		day, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "calendar-verycomplete":
+			stars[day] = 2
+		case "calendar-complete":
+			stars[day] = 1
+		}
+	}
+	return stars
+}
+
+func parsePartResult(solveTime, rank, score string) PartResult {
+	if solveTime == "-" {
+		return PartResult{}
+	}
+	result := PartResult{Solved: true, Time: solveTime}
+	result.Rank, _ = strconv.Atoi(rank)
+	result.Score, _ = strconv.Atoi(score)
+	return result
+}
+
+// parseSelfLeaderboard reads the time, rank and score of each part from the personal leaderboard page
+func parseSelfLeaderboard(page string) map[int]DayResult {
+	results := map[int]DayResult{}
+	for _, line := range strings.Split(tagPattern.ReplaceAllString(page, ""), "\n") {
+		match := selfRowPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		day, _ := strconv.Atoi(match[1])
+		if day < 1 || day > lastDay {
+			continue
+		}
+		results[day] = DayResult{
+			Day:   day,
+			Part1: parsePartResult(match[2], match[3], match[4]),
+			Part2: parsePartResult(match[5], match[6], match[7]),
+		}
+	}
+	return results
+}
+
+// fetchYearStats combines the calendar and personal leaderboard of an event
+func fetchYearStats(year int, cookie http.Cookie) (stats YearStats, err error) {
+	stats.Year = year
+
+	calendar, err := fetchPage(calendarURL(year), cookie)
+	if err != nil {
+		return stats, err
+	}
+
+	self, err := fetchPage(selfLeaderboardURL(year), cookie)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Days = dayResults(parseCalendar(string(calendar)), parseSelfLeaderboard(string(self)))
+	return stats, nil
+}
+
+// fetchYearStars reads only the stars of an event from its calendar, which is cached
+func fetchYearStars(year int, cookie http.Cookie, now time.Time) (stats YearStats, err error) {
+	stats.Year = year
+
+	calendar, err := cachedFetchCalendar(year, cookie, now)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Days = dayResults(parseCalendar(string(calendar)), nil)
+	return stats, nil
+}
+
+// dayResults combines the stars and personal results of each day
+func dayResults(stars map[int]int, results map[int]DayResult) (days []DayResult) {
+	for day := 1; day <= lastDay; day++ {
+		result := results[day]
+		result.Day = day
+		result.Stars = stars[day]
+		days = append(days, result)
+	}
+	return days
+}
+
+func starMarker(stars int) string {
+	switch stars {
+	case 2:
+		return "**"
+	case 1:
+		return "* "
+	}
+	return "  "
+}
+
+// renderCalendar draws December as a calendar with the stars of each day
+func renderCalendar(w io.Writer, stats YearStats) {
+	fmt.Fprintf(w, "December %d, %d stars\n", stats.Year, stats.Stars())
+	fmt.Fprintln(w, " Mo    Tu    We    Th    Fr    Sa    Su")
+
+	offset := (int(time.Date(stats.Year, time.December, 1, 0, 0, 0, 0, time.UTC).Weekday()) + 6) % 7
+	line := strings.Repeat("      ", offset)
+	for day := 1; day <= lastDay; day++ {
+		line += fmt.Sprintf("%3d%s ", day, starMarker(stats.Day(day).Stars))
+		if (offset+day)%7 == 0 {
+			fmt.Fprintln(w, strings.TrimRight(line, " "))
+			line = ""
+		}
+	}
+	if line != "" {
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+}
+
+func formatPart(result PartResult) string {
+	if !result.Solved {
+		return "-\t-\t-"
+	}
+	return fmt.Sprintf("%s\t%d\t%d", result.Time, result.Rank, result.Score)
+}
+
+// renderYearStats writes the calendar and the personal time and rank for each part
+func renderYearStats(w io.Writer, stats YearStats) error {
+	renderCalendar(w, stats)
+	fmt.Fprintln(w)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Day\tStars\tPart 1\tRank\tScore\tPart 2\tRank\tScore")
+	for day := lastDay; day >= 1; day-- {
+		result := stats.Day(day)
+		if result.Stars == 0 && !result.Part1.Solved {
+			continue
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\n", day, strings.TrimSpace(starMarker(result.Stars)), formatPart(result.Part1), formatPart(result.Part2))
+	}
+	return table.Flush()
+}
+
+// renderAllYears writes a line of stars for every event
+func renderAllYears(w io.Writer, allStats []YearStats) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Year\tStars\tDays")
+
+	total := 0
+	for _, stats := range allStats {
+		var days strings.Builder
+		for day := 1; day <= lastDay; day++ {
+			days.WriteByte(starSymbol(stats.Day(day).Stars))
+		}
+		total += stats.Stars()
+		fmt.Fprintf(table, "%d\t%d\t%s\n", stats.Year, stats.Stars(), days.String())
+	}
+	fmt.Fprintf(table, "Total\t%d\t\n", total)
+	return table.Flush()
+}
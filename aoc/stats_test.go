@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const mockCalendarPage = `<pre class="calendar">
+<a aria-label="Day 1, two stars" href="/2022/day/1" class="calendar-day1 calendar-verycomplete">...</a>
+<a aria-label="Day 2, one star" href="/2022/day/2" class="calendar-day2 calendar-complete">...</a>
+<a aria-label="Day 3" href="/2022/day/3" class="calendar-day3">...</a>
+</pre>`
+
+const mockSelfPage = `<article><p>These are your personal leaderboard statistics.</p>
+<pre>      <span class="leaderboard-daydesc-first">--------Part 1--------</span>   <span class="leaderboard-daydesc-both">--------Part 2--------</span>
+Day   <span class="leaderboard-daydesc-first">    Time   Rank  Score</span>   <span class="leaderboard-daydesc-both">    Time   Rank  Score</span>
+  2   00:12:34   1234      0          -      -      -
+  1   00:05:12     87     14   00:08:01     95      6
+</pre></article>`
+
+func TestParsingCalendar(t *testing.T) {
+	stars := parseCalendar(mockCalendarPage)
+
+	expected := map[int]int{1: 2, 2: 1, 3: 0}
+	for day, count := range expected {
+		if stars[day] != count {
+			t.Errorf("Expected %d stars on day %d, got %d", count, day, stars[day])
+		}
+	}
+}
+
+func TestParsingSelfLeaderboard(t *testing.T) {
+	results := parseSelfLeaderboard(mockSelfPage)
+
+	if len(results) != 2 {
+		t.Fatalf("Expected results for 2 days, got %d", len(results))
+	}
+
+	first := results[1]
+	if !first.Part1.Solved || first.Part1.Time != "00:05:12" || first.Part1.Rank != 87 || first.Part1.Score != 14 {
+		t.Errorf("Unexpected result for day 1 part 1: %+v", first.Part1)
+	}
+	if !first.Part2.Solved || first.Part2.Rank != 95 {
+		t.Errorf("Unexpected result for day 1 part 2: %+v", first.Part2)
+	}
+	if results[2].Part2.Solved {
+		t.Error("Expected day 2 part 2 to be unsolved")
+	}
+}
+
+type mockPageClient struct {
+	pages map[string]string
+}
+
+func (c *mockPageClient) Do(req *http.Request) (*http.Response, error) {
+	page, ok := c.pages[req.URL.Path]
+	if !ok {
+		return &http.Response{StatusCode: 404, Status: "404 Not Found", Body: mockBody("")}, nil
+	}
+	return &http.Response{StatusCode: 200, Body: mockBody(page)}, nil
+}
+
+func TestFetchingYearStats(t *testing.T) {
+	cookie := http.Cookie{
+		Name:  "session",
+		Value: "abc123",
+	}
+	client = &mockPageClient{pages: map[string]string{
+		"/2022":                  mockCalendarPage,
+		"/2022/leaderboard/self": mockSelfPage,
+	}}
+
+	stats, err := fetchYearStats(2022, cookie)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Stars() != 3 {
+		t.Errorf("Expected 3 stars, got %d", stats.Stars())
+	}
+	if len(stats.Days) != lastDay || stats.Days[0].Day != 1 {
+		t.Errorf("Expected days 1 to %d, got %+v", lastDay, stats.Days)
+	}
+	if stats.Day(1).Part1.Rank != 87 {
+		t.Errorf("Expected day 1 part 1 rank 87, got %d", stats.Day(1).Part1.Rank)
+	}
+
+	if _, err := fetchYearStats(2021, cookie); err == nil {
+		t.Error("Expected an error for a missing page")
+	}
+}
+
+func TestRenderingCalendar(t *testing.T) {
+	stats := YearStats{Year: 2022, Days: []DayResult{{Day: 1, Stars: 2}, {Day: 2, Stars: 1}}}
+
+	var output bytes.Buffer
+	renderCalendar(&output, stats)
+
+	lines := strings.Split(output.String(), "\n")
+	// December 1st 2022 was a Thursday
+	if expected := strings.Repeat(" ", 18) + "  1**   2*    3     4"; lines[2] != expected {
+		t.Errorf("Expected first week:\n%q\ngot:\n%q", expected, lines[2])
+	}
+}
+
+func TestSummarizingEveryYear(t *testing.T) {
+	mockCacheDir(t)
+	mockClock(t, time.Now())
+	client = &mockPageClient{pages: map[string]string{"/2015": mockCalendarPage, "/2016": mockCalendarPage}}
+
+	run := func() (code int, output string) {
+		var stdout, stderr bytes.Buffer
+		code = execute([]string{"stats", "-session", "abc123", "-now", "2016-12-10"}, &stdout, &stderr)
+		return code, stdout.String() + stderr.String()
+	}
+
+	t.Run("Should only fetch the calendars", func(t *testing.T) {
+		if code, output := run(); code != 0 || !strings.Contains(output, "Total  6") {
+			t.Errorf("Expected 6 stars without the personal leaderboards, got %d: %s", code, output)
+		}
+	})
+
+	t.Run("Should cache the calendars", func(t *testing.T) {
+		client = &mockPageClient{}
+		if code, output := run(); code != 0 || !strings.Contains(output, "Total  6") {
+			t.Errorf("Expected the cached calendars, got %d: %s", code, output)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Verdict is how advent of code responded to an answer
+type Verdict int
+
+const (
+	unknownVerdict Verdict = iota
+	correctVerdict
+	wrongVerdict
+	tooRecentVerdict
+	wrongLevelVerdict
+)
+
+var answerArticlePattern = regexp.MustCompile(`(?s)<article>(.*?)</article>`)
+
+func answerURL(year, day int) string {
+	return fmt.Sprintf("%s/%d/day/%d/answer", baseURL(), year, day)
+}
+
+// parseVerdict reads the verdict and its message from the page returned after submitting an answer
+func parseVerdict(page string) (verdict Verdict, message string) {
+	message = page
+	if match := answerArticlePattern.FindStringSubmatch(page); match != nil {
+		message = match[1]
+	}
+	message = strings.Join(strings.Fields(pageText(message)), " ")
+
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		return correctVerdict, message
+	case strings.Contains(message, "That's not the right answer"):
+		return wrongVerdict, message
+	case strings.Contains(message, "You gave an answer too recently"):
+		return tooRecentVerdict, message
+	case strings.Contains(message, "You don't seem to be solving the right level"):
+		return wrongLevelVerdict, message
+	}
+	return unknownVerdict, message
+}
+
+// submitAnswer sends an answer for a part and returns advent of code's verdict
+func submitAnswer(key PartKey, answer string, cookie http.Cookie) (verdict Verdict, message string, err error) {
+	form := url.Values{
+		"level":  {strconv.Itoa(key.Part)},
+		"answer": {answer},
+	}
+
+	res, err := post(answerURL(key.Year, key.Day), form, cookie)
+	if err != nil {
+		return verdict, message, err
+	}
+	defer res.Body.Close()
+
+	page, err := io.ReadAll(res.Body)
+	if err != nil {
+		return verdict, message, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return verdict, message, statusError("submit answer", res, page)
+	}
+
+	verdict, message = parseVerdict(string(page))
+	return verdict, message, nil
+}
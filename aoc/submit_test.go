@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/streakinthesky/adventofcode-fetcher/aoc/aoctest"
+)
+
+const (
+	mockCorrectPage    = `<main><article><p>That's the right answer!  You are <span class="day-success">one gold star</span> closer to collecting enough star fruit.</p></article></main>`
+	mockWrongPage      = `<main><article><p>That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data.</p></article></main>`
+	mockTooRecentPage  = `<main><article><p>You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 37s left to wait.</p></article></main>`
+	mockWrongLevelPage = `<main><article><p>You don't seem to be solving the right level.  Did you already complete it?</p></article></main>`
+)
+
+func TestParsingVerdict(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    string
+		verdict Verdict
+	}{
+		{name: "correct", page: mockCorrectPage, verdict: correctVerdict},
+		{name: "wrong", page: mockWrongPage, verdict: wrongVerdict},
+		{name: "too recent", page: mockTooRecentPage, verdict: tooRecentVerdict},
+		{name: "wrong level", page: mockWrongLevelPage, verdict: wrongLevelVerdict},
+		{name: "unknown", page: "<html></html>", verdict: unknownVerdict},
+	}
+
+	for _, test := range tests {
+		t.Run("Should parse "+test.name+" answers", func(t *testing.T) {
+			if verdict, message := parseVerdict(test.page); verdict != test.verdict {
+				t.Errorf("Expected verdict %d, got %d for %s", test.verdict, verdict, message)
+			}
+		})
+	}
+
+	t.Run("Should return the message as text", func(t *testing.T) {
+		expected := "That's the right answer! You are one gold star closer to collecting enough star fruit."
+
+		if _, message := parseVerdict(mockCorrectPage); message != expected {
+			t.Errorf("Expected %s, got %s", expected, message)
+		}
+	})
+}
+
+type mockSubmitClient struct {
+	req  *http.Request
+	body string
+}
+
+func (c *mockSubmitClient) Do(req *http.Request) (*http.Response, error) {
+	c.req = req
+	body, _ := io.ReadAll(req.Body)
+	c.body = string(body)
+	return &http.Response{StatusCode: 200, Body: mockBody(mockCorrectPage)}, nil
+}
+
+func TestSubmittingAnswer(t *testing.T) {
+	mock := &mockSubmitClient{}
+	client = mock
+	cookie := http.Cookie{
+		Name:  "session",
+		Value: "abc123",
+	}
+
+	verdict, _, err := submitAnswer(PartKey{Year: 2022, Day: 5, Part: 2}, "CMZ", cookie)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verdict != correctVerdict {
+		t.Errorf("Expected a correct verdict, got %d", verdict)
+	}
+
+	if mock.req.Method != "POST" || mock.req.URL.String() != "https://adventofcode.com/2022/day/5/answer" {
+		t.Errorf("Expected a POST to the answer url, got %s %s", mock.req.Method, mock.req.URL)
+	}
+	if mock.body != "answer=CMZ&level=2" {
+		t.Errorf("Expected the answer and level in the form, got %s", mock.body)
+	}
+	if _, err := mock.req.Cookie("session"); err != nil {
+		t.Error("Expected the session cookie to be sent")
+	}
+}
+
+func TestSubmittingLoggedOut(t *testing.T) {
+	server := aoctest.NewServer(aoctest.NewClock(time.Date(2022, time.December, 5, 0, 0, 0, 0, aoctest.EventLocation)))
+	t.Cleanup(server.Close)
+	mockBaseURL(t, server.URL)
+	client = &http.Client{}
+
+	_, _, err := submitAnswer(PartKey{Year: 2022, Day: 5, Part: 1}, "CMZ", http.Cookie{Name: "session", Value: "other"})
+	if exitCode(err) != exitSession {
+		t.Errorf("Expected exit code %d, got %d: %v", exitSession, exitCode(err), err)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// VerifyResult is whether a solution still gets a part's accepted answer
+type VerifyResult struct {
+	Key      PartKey `json:"part"`
+	Expected string  `json:"expected"`
+	Actual   string  `json:"actual"`
+	Err      error   `json:"-"`
+}
+
+// MarshalJSON writes the reason the solution could not be checked as an error message
+func (r VerifyResult) MarshalJSON() ([]byte, error) {
+	// result has the fields of VerifyResult without its methods, so it is marshalled as usual
+	type result VerifyResult
+	message := ""
+	if r.Err != nil {
+		message = r.Err.Error()
+	}
+	return json.Marshal(struct {
+		result
+		Error string `json:"error,omitempty"`
+	}{result(r), message})
+}
+
+// Passed returns whether the solution output the accepted answer
+func (r VerifyResult) Passed() bool {
+	return r.Err == nil && r.Actual == r.Expected
+}
+
+// verifyAnswers runs the solution of every day with an accepted answer, once per day, and compares
+// the answer of each part. When year is not 0, only that year is verified.
+func verifyAnswers(pack Pack, ledger Ledger, pattern string, year int) (results []VerifyResult, err error) {
+	keys, err := ledger.Accepted()
+	if err != nil {
+		return results, err
+	}
+
+	outputs := map[string]string{}
+	errs := map[string]error{}
+	for _, key := range keys {
+		if year != 0 && key.Year != year {
+			continue
+		}
+
+		result := VerifyResult{Key: key, Expected: ledger.Entry(key).Correct}
+		dir := dayDir(key.Year, key.Day)
+		if _, ran := outputs[dir]; !ran {
+			outputs[dir], errs[dir] = runSolution(pack, dir)
+		}
+
+		if result.Err = errs[dir]; result.Err == nil {
+			result.Actual, result.Err = extractAnswer(outputs[dir], pattern, key.Part)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// renderVerify writes a line for every part that no longer gets its accepted answer, and a summary
+func renderVerify(w io.Writer, results []VerifyResult) (failures int, err error) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, result := range results {
+		if result.Passed() {
+			continue
+		}
+
+		if failures == 0 {
+			fmt.Fprintln(table, "Year\tDay\tPart\tExpected\tGot")
+		}
+		failures++
+
+		actual := result.Actual
+		if result.Err != nil {
+			actual = result.Err.Error()
+		}
+		fmt.Fprintf(table, "%d\t%d\t%d\t%s\t%s\n", result.Key.Year, result.Key.Day, result.Key.Part, result.Expected, actual)
+	}
+	if err := table.Flush(); err != nil {
+		return failures, err
+	}
+
+	fmt.Fprintf(w, "%d of %d answers verified\n", len(results)-failures, len(results))
+	return failures, nil
+}
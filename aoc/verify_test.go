@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyingAnswers(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is not available")
+	}
+	readFile = os.ReadFile
+	chdir(t)
+
+	// the "solution" prints its input, so the inputs are the answers
+	inputs := map[int]string{
+		1: "Part 1: 24000\nPart 2: 45000\n",
+		2: "Part 1: 15\nPart 2: 13\n",
+	}
+	for day, input := range inputs {
+		os.MkdirAll(dayDir(2022, day), 0o755)
+		os.WriteFile(filepath.Join(dayDir(2022, day), inputFileName), []byte(input), 0o644)
+	}
+
+	ledger := Ledger{Answers: map[string]LedgerEntry{}}
+	ledger.Record(PartKey{Year: 2022, Day: 1, Part: 1}, "24000", true)
+	ledger.Record(PartKey{Year: 2022, Day: 1, Part: 2}, "45000", true)
+	ledger.Record(PartKey{Year: 2022, Day: 2, Part: 1}, "15", true)
+	ledger.Record(PartKey{Year: 2022, Day: 2, Part: 2}, "12", true)
+	ledger.Record(PartKey{Year: 2022, Day: 3, Part: 1}, "157", true)
+	ledger.Record(PartKey{Year: 2021, Day: 1, Part: 1}, "1502", true)
+	ledger.Record(PartKey{Year: 2022, Day: 4, Part: 1}, "2", false)
+
+	pack := Pack{Name: "cat", Run: "cat {input}"}
+
+	t.Run("Should verify every accepted answer of the year", func(t *testing.T) {
+		results, err := verifyAnswers(pack, ledger, defaultAnswerPattern, 2022)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 5 {
+			t.Fatalf("Expected 5 results, got %d", len(results))
+		}
+
+		var output bytes.Buffer
+		failures, err := renderVerify(&output, results)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if failures != 2 {
+			t.Errorf("Expected day 2 part 2 and day 3 to fail, got %d failures:\n%s", failures, output.String())
+		}
+		if !strings.Contains(output.String(), "2022  2    2     12        13") {
+			t.Errorf("Expected the wrong answer in the summary, got:\n%s", output.String())
+		}
+		if !strings.Contains(output.String(), "3 of 5 answers verified") {
+			t.Errorf("Expected a summary, got:\n%s", output.String())
+		}
+
+		content, err := json.Marshal(results)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), `"error":"open `) {
+			t.Errorf("Expected the reason day 3 failed, got %s", content)
+		}
+	})
+
+	t.Run("Should verify every year", func(t *testing.T) {
+		results, err := verifyAnswers(pack, ledger, defaultAnswerPattern, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 6 || results[0].Key.Year != 2021 {
+			t.Errorf("Expected 6 results starting with 2021, got %+v", results)
+		}
+	})
+}
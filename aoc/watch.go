@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var sleep = time.Sleep
+
+// Event is a change between two snapshots of a leaderboard
+type Event struct {
+	Type     string    `json:"type"`
+	MemberID int       `json:"member_id"`
+	Member   string    `json:"member"`
+	Day      int       `json:"day,omitempty"`
+	Part     int       `json:"part,omitempty"`
+	OldRank  int       `json:"old_rank,omitempty"`
+	NewRank  int       `json:"new_rank,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+const (
+	newStarEvent    = "new_star"
+	rankChangeEvent = "rank_change"
+	newMemberEvent  = "new_member"
+)
+
+// Message describes the event for chat messages
+func (e Event) Message() string {
+	switch e.Type {
+	case newStarEvent:
+		return fmt.Sprintf("%s solved day %d part %d", e.Member, e.Day, e.Part)
+	case rankChangeEvent:
+		return fmt.Sprintf("%s moved from rank %d to %d", e.Member, e.OldRank, e.NewRank)
+	case newMemberEvent:
+		return fmt.Sprintf("%s joined the leaderboard", e.Member)
+	}
+	return e.Type
+}
+
+func memberRanks(leaderboard Leaderboard) map[int]int {
+	ranks := map[int]int{}
+	for i, member := range rankMembers(leaderboard) {
+		ranks[member.ID] = i + 1
+	}
+	return ranks
+}
+
+// diffLeaderboards returns the events between two snapshots of a leaderboard, in the order they happened
+func diffLeaderboards(previous, current Leaderboard, now time.Time) (events []Event) {
+	oldRanks := memberRanks(previous)
+	newRanks := memberRanks(current)
+
+	for _, member := range rankMembers(current) {
+		old, existed := previous.Members[strconv.Itoa(member.ID)]
+		if !existed {
+			events = append(events, Event{Type: newMemberEvent, MemberID: member.ID, Member: member.DisplayName(), Time: now})
+			continue
+		}
+
+		for day := 1; day <= lastDay; day++ {
+			for part := 1; part <= 2; part++ {
+				solvedAt, solved := member.Solved(day, part)
+				if _, solvedBefore := old.Solved(day, part); solved && !solvedBefore {
+					events = append(events, Event{Type: newStarEvent, MemberID: member.ID, Member: member.DisplayName(), Day: day, Part: part, Time: solvedAt})
+				}
+			}
+		}
+
+		if oldRanks[member.ID] != newRanks[member.ID] {
+			events = append(events, Event{
+				Type:     rankChangeEvent,
+				MemberID: member.ID,
+				Member:   member.DisplayName(),
+				OldRank:  oldRanks[member.ID],
+				NewRank:  newRanks[member.ID],
+				Time:     now,
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
+type sink interface {
+	Send(events []Event) error
+}
+
+// webhookSink posts events to a url, as JSON or as a Slack or Discord message
+type webhookSink struct {
+	url    string
+	format string
+}
+
+func (s webhookSink) payload(events []Event) (interface{}, error) {
+	messages := make([]string, len(events))
+	for i, event := range events {
+		messages[i] = event.Message()
+	}
+
+	switch s.format {
+	case "json":
+		return map[string][]Event{"events": events}, nil
+	case "slack":
+		return map[string]string{"text": strings.Join(messages, "\n")}, nil
+	case "discord":
+		return map[string]string{"content": strings.Join(messages, "\n")}, nil
+	}
+	return nil, fmt.Errorf("%s is not a webhook format, use json, slack or discord", s.format)
+}
+
+func (s webhookSink) Send(events []Event) error {
+	payload, err := s.payload(events)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := transport().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("Webhook %s responded with %s", s.url, res.Status)
+	}
+	return nil
+}
+
+// commandSink runs a command with the events as JSON on its stdin
+type commandSink struct {
+	command string
+	stdout  io.Writer
+}
+
+func (s commandSink) Send(events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	exitCode, err := runCommand(s.command, "-", body, s.stdout)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("Hook %s exited with %d", s.command, exitCode)
+	}
+	return nil
+}
+
+// watcher polls a leaderboard and sends the changes to its sinks
+type watcher struct {
+	year     int
+	id       string
+	cookie   http.Cookie
+	sinks    []sink
+	previous *Leaderboard
+}
+
+// poll fetches the leaderboard through the cache and sends any changes since the last poll.
+// The first poll only records the leaderboard.
+func (w *watcher) poll(now time.Time) (events []Event, err error) {
+	body, _, err := cachedFetchLeaderboard(w.year, w.id, w.cookie, now)
+	if err != nil {
+		return events, err
+	}
+
+	current, err := parseLeaderboard(body)
+	if err != nil {
+		return events, err
+	}
+
+	if w.previous != nil {
+		events = diffLeaderboards(*w.previous, current, now)
+	}
+	w.previous = &current
+
+	if len(events) == 0 {
+		return events, nil
+	}
+
+	var errs []string
+	for _, s := range w.sinks {
+		if err := s.Send(events); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return events, errors.New(strings.Join(errs, "; "))
+	}
+	return events, nil
+}
+
+// watch polls the leaderboard forever, never faster than the polling limit
+func (w *watcher) watch(stdout, stderr io.Writer, interval time.Duration) error {
+	if interval < leaderboardPollInterval {
+		interval = leaderboardPollInterval
+	}
+
+	for {
+		events, err := w.poll(wallClock.Now())
+		for _, event := range events {
+			fmt.Fprintln(stdout, event.Message())
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+		}
+
+		sleep(interval)
+	}
+}
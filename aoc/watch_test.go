@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockNextLeaderboard is mockLeaderboard after bob solved day 2 part 2 and took first place, and dave joined
+func mockNextLeaderboard(t *testing.T) Leaderboard {
+	leaderboard := mockLeaderboard(t)
+
+	bob := leaderboard.Members["2"]
+	bob.CompletionDayLevel["2"]["2"] = Star{GetStarTs: 1669960100, StarIndex: 13}
+	bob.Stars = 4
+	bob.LocalScore = 12
+	leaderboard.Members["2"] = bob
+
+	leaderboard.Members["4"] = Member{ID: 4, Name: "dave"}
+	return leaderboard
+}
+
+func TestDiffingLeaderboards(t *testing.T) {
+	now := time.Unix(1669961000, 0)
+	events := diffLeaderboards(mockLeaderboard(t), mockNextLeaderboard(t), now)
+
+	expected := []Event{
+		{Type: newStarEvent, MemberID: 2, Day: 2, Part: 2},
+		{Type: rankChangeEvent, MemberID: 2, OldRank: 2, NewRank: 1},
+		{Type: rankChangeEvent, MemberID: 1, OldRank: 1, NewRank: 2},
+		{Type: newMemberEvent, MemberID: 4},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %v", len(expected), events)
+	}
+	for i, event := range expected {
+		actual := events[i]
+		if actual.Type != event.Type || actual.MemberID != event.MemberID || actual.Day != event.Day ||
+			actual.Part != event.Part || actual.OldRank != event.OldRank || actual.NewRank != event.NewRank {
+			t.Errorf("Expected event %+v, got %+v", event, actual)
+		}
+	}
+}
+
+func TestSendingToWebhooks(t *testing.T) {
+	events := []Event{{Type: newMemberEvent, MemberID: 4, Member: "dave"}}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: "json", expected: `"type":"new_member"`},
+		{format: "slack", expected: `{"text":"dave joined the leaderboard"}`},
+		{format: "discord", expected: `{"content":"dave joined the leaderboard"}`},
+	}
+
+	for _, test := range tests {
+		t.Run("Should post "+test.format+" payloads", func(t *testing.T) {
+			var received string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+			}))
+			defer server.Close()
+			client = server.Client()
+
+			if err := (webhookSink{url: server.URL, format: test.format}).Send(events); err != nil {
+				t.Fatalf("Should not have error, got error: %s", err.Error())
+			}
+			if !strings.Contains(received, test.expected) {
+				t.Errorf("Expected payload to contain %s, got %s", test.expected, received)
+			}
+		})
+	}
+
+	t.Run("Should return error when the webhook fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		client = server.Client()
+
+		if err := (webhookSink{url: server.URL, format: "json"}).Send(events); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Should return error for unknown format", func(t *testing.T) {
+		if err := (webhookSink{url: "http://localhost", format: "xml"}).Send(events); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+type mockSink struct {
+	events []Event
+}
+
+func (s *mockSink) Send(events []Event) error {
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func TestPollingLeaderboard(t *testing.T) {
+	mockCacheDir(t)
+	now := getNow(t, INSIDE_ADVENT_DATE)
+	s := &mockSink{}
+	w := watcher{year: 2022, id: "1", cookie: http.Cookie{Name: "session", Value: "abc123"}, sinks: []sink{s}}
+
+	client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(mockLeaderboardJSON)}}
+	if events, err := w.poll(now); err != nil || len(events) != 0 {
+		t.Fatalf("Expected the first poll to only record the leaderboard, got %v, %v", events, err)
+	}
+
+	next, _ := json.Marshal(mockNextLeaderboard(t))
+	client = &mockClient{res: http.Response{StatusCode: 200, Body: mockBody(string(next))}}
+
+	if events, err := w.poll(now.Add(time.Minute)); err != nil || len(events) != 0 {
+		t.Errorf("Expected the cached leaderboard within the polling limit, got %v, %v", events, err)
+	}
+
+	if _, err := w.poll(now.Add(leaderboardPollInterval)); err != nil {
+		t.Fatalf("Should not have error, got error: %s", err.Error())
+	}
+	if len(s.events) != 4 {
+		t.Errorf("Expected 4 events sent to the sink, got %v", s.events)
+	}
+}